@@ -0,0 +1,101 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerActivityRoutes(r *Router) {
+	r.GET("/api/v1/me/activity", RoleMiddleware("user")(GetMyActivity))
+	r.GET("/api/v1/admin/users/:id/activity", RoleMiddleware("admin")(GetUserActivity))
+}
+
+// UserActivity is one entry in a user's event timeline.
+type UserActivity struct {
+	ID        int64  `json:"id"`
+	UserID    int64  `json:"user_id"`
+	Kind      string `json:"kind"`
+	Detail    string `json:"detail"`
+	RefID     int64  `json:"ref_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// recordActivity appends one event to a user's timeline. Failures are logged
+// rather than surfaced: the timeline is for support and fraud review, never
+// a reason to fail the action itself.
+func recordActivity(userID int64, kind, detail string, refID int64) {
+	if _, err := db.Exec(
+		"INSERT INTO user_activities (user_id, kind, detail, ref_id, created_at) VALUES (?, ?, ?, ?, ?)",
+		userID, kind, detail, refID, nowUTC()); err != nil {
+		log.Printf("gagal mencatat aktivitas user %d: %v", userID, err)
+	}
+}
+
+func listActivity(w http.ResponseWriter, r *http.Request, userID int64) {
+	k := keysetParams(r)
+	query := "SELECT id, user_id, kind, detail, ref_id, created_at FROM user_activities WHERE user_id = ?"
+	args := []any{userID}
+	if kind := r.URL.Query().Get("kind"); kind != "" {
+		query += " AND kind = ?"
+		args = append(args, kind)
+	}
+	if k.Enabled {
+		if k.Cursor > 0 {
+			query += " AND id < ?"
+			args = append(args, k.Cursor)
+		}
+		query += " ORDER BY id DESC LIMIT ?"
+		args = append(args, k.Limit)
+	} else {
+		query += " ORDER BY id DESC LIMIT 50"
+	}
+	rows, err := readOnlyDB().Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	activities := []UserActivity{}
+	for rows.Next() {
+		var (
+			a       UserActivity
+			created time.Time
+		)
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Kind, &a.Detail, &a.RefID, &created); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		a.CreatedAt = displayTime(created)
+		activities = append(activities, a)
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(activities) > 0 {
+			lastID = activities[len(activities)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       activities,
+			"next_cursor": nextCursor(lastID, len(activities), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, activities)
+}
+
+// GetMyActivity shows the caller their own timeline, optionally filtered by
+// ?kind=.
+func GetMyActivity(w http.ResponseWriter, r *http.Request) {
+	listActivity(w, r, currentUserID(r))
+}
+
+// GetUserActivity is the admin view of one user's timeline, for support and
+// fraud investigations.
+func GetUserActivity(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	listActivity(w, r, id)
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerAddressRoutes(r *Router) {
+	userOnly := RoleMiddleware("user")
+	r.GET("/api/v1/me/addresses", userOnly(GetMyAddresses))
+	r.POST("/api/v1/me/addresses", userOnly(CreateAddress))
+	r.PUT("/api/v1/me/addresses/:id", userOnly(UpdateAddress))
+	r.DELETE("/api/v1/me/addresses/:id", userOnly(DeleteAddress))
+	r.POST("/api/v1/me/addresses/:id/default", userOnly(SetDefaultAddress))
+}
+
+// Address is a saved shipping destination in a user's address book.
+type Address struct {
+	ID         int64  `json:"id"`
+	Label      string `json:"label"`
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	IsDefault  bool   `json:"is_default"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type addressRequest struct {
+	Label      string `json:"label"`
+	Recipient  string `json:"recipient"`
+	Phone      string `json:"phone"`
+	Street     string `json:"street"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+func (req *addressRequest) valid() bool {
+	return req.Label != "" && req.Recipient != "" && req.Phone != "" &&
+		req.Street != "" && req.City != "" && req.PostalCode != ""
+}
+
+// GetMyAddresses lists the caller's address book, default first.
+func GetMyAddresses(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	rows, err := db.Query(
+		`SELECT id, label, recipient, phone, street, city, postal_code, is_default, created_at
+		 FROM addresses WHERE user_id = ? ORDER BY is_default DESC, id DESC`, userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	addresses := []Address{}
+	for rows.Next() {
+		var (
+			a       Address
+			created time.Time
+		)
+		if err := rows.Scan(&a.ID, &a.Label, &a.Recipient, &a.Phone, &a.Street,
+			&a.City, &a.PostalCode, &a.IsDefault, &created); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		a.CreatedAt = displayTime(created)
+		addresses = append(addresses, a)
+	}
+	respondJSON(w, http.StatusOK, addresses)
+}
+
+// CreateAddress adds an address; the first one automatically becomes the
+// default so checkout always has a destination to fall back on.
+func CreateAddress(w http.ResponseWriter, r *http.Request) {
+	var req addressRequest
+	if err := decodeBody(r, &req); err != nil || !req.valid() {
+		respondError(w, http.StatusBadRequest, "label, recipient, phone, street, city, dan postal_code wajib diisi")
+		return
+	}
+	userID := currentUserID(r)
+	var id int64
+	err := WithTx(func(tx *sql.Tx) error {
+		var count int
+		if err := tx.QueryRow("SELECT COUNT(*) FROM addresses WHERE user_id = ?", userID).
+			Scan(&count); err != nil {
+			return err
+		}
+		isDefault := req.IsDefault || count == 0
+		if isDefault {
+			if _, err := tx.Exec("UPDATE addresses SET is_default = 0 WHERE user_id = ?", userID); err != nil {
+				return err
+			}
+		}
+		res, err := tx.Exec(
+			`INSERT INTO addresses (user_id, label, recipient, phone, street, city, postal_code, is_default, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			userID, req.Label, req.Recipient, req.Phone, req.Street, req.City,
+			req.PostalCode, isDefault, nowUTC())
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id})
+}
+
+// UpdateAddress rewrites one of the caller's addresses; rows belonging to
+// someone else look like a 404.
+func UpdateAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req addressRequest
+	if err := decodeBody(r, &req); err != nil || !req.valid() {
+		respondError(w, http.StatusBadRequest, "label, recipient, phone, street, city, dan postal_code wajib diisi")
+		return
+	}
+	userID := currentUserID(r)
+	err = WithTx(func(tx *sql.Tx) error {
+		if req.IsDefault {
+			if _, err := tx.Exec("UPDATE addresses SET is_default = 0 WHERE user_id = ?", userID); err != nil {
+				return err
+			}
+		}
+		res, err := tx.Exec(
+			`UPDATE addresses SET label = ?, recipient = ?, phone = ?, street = ?, city = ?, postal_code = ?, is_default = ?
+			 WHERE id = ? AND user_id = ?`,
+			req.Label, req.Recipient, req.Phone, req.Street, req.City, req.PostalCode,
+			req.IsDefault, id, userID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "alamat tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "alamat berhasil diperbarui")
+}
+
+// DeleteAddress removes an address; deleting the default promotes the most
+// recent remaining one so the book never silently loses its default.
+func DeleteAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	userID := currentUserID(r)
+	err = WithTx(func(tx *sql.Tx) error {
+		var wasDefault bool
+		err := tx.QueryRow("SELECT is_default FROM addresses WHERE id = ? AND user_id = ?",
+			id, userID).Scan(&wasDefault)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM addresses WHERE id = ?", id); err != nil {
+			return err
+		}
+		if wasDefault {
+			if _, err := tx.Exec(
+				`UPDATE addresses SET is_default = 1 WHERE user_id = ?
+				 ORDER BY id DESC LIMIT 1`, userID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "alamat tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "alamat berhasil dihapus")
+}
+
+// SetDefaultAddress marks one address as the default shipping destination.
+func SetDefaultAddress(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	userID := currentUserID(r)
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec("UPDATE addresses SET is_default = 1 WHERE id = ? AND user_id = ?",
+			id, userID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return sql.ErrNoRows
+		}
+		_, err = tx.Exec("UPDATE addresses SET is_default = 0 WHERE user_id = ? AND id <> ?",
+			userID, id)
+		return err
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "alamat tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "alamat default berhasil diatur")
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerAdminOrderRoutes(r *Router) {
+	r.POST("/api/v1/admin/orders", RoleMiddleware("admin", "cashier")(CreateAdminOrder))
+}
+
+// CreateAdminOrder builds an order for a chosen customer — the phone-order
+// use case. Lines come from the request instead of a cart, but pricing,
+// promotions, and stock reservations go through the same services as the
+// customer checkout.
+func CreateAdminOrder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID        int64  `json:"user_id"`
+		PaymentMethod string `json:"payment_method"`
+		DeliveryArea  string `json:"delivery_area"`
+		Items         []struct {
+			ProductID *int64 `json:"product_id"`
+			VariantID *int64 `json:"variant_id"`
+			BundleID  *int64 `json:"bundle_id"`
+			Quantity  int    `json:"quantity"`
+		} `json:"items"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.UserID == 0 || len(req.Items) == 0 {
+		respondError(w, http.StatusBadRequest, "user_id dan items wajib diisi")
+		return
+	}
+	if req.PaymentMethod == "" {
+		req.PaymentMethod = "transfer"
+	}
+	if req.PaymentMethod != "transfer" && req.PaymentMethod != "cod" {
+		respondError(w, http.StatusBadRequest, "payment_method harus transfer atau cod")
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM users WHERE id = ?", req.UserID).Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists == 0 {
+		respondError(w, http.StatusNotFound, "user tidak ditemukan")
+		return
+	}
+
+	var (
+		lines []checkoutLine
+		total float64
+	)
+	for _, it := range req.Items {
+		if it.Quantity <= 0 {
+			respondError(w, http.StatusBadRequest, "quantity minimal 1")
+			return
+		}
+		var l checkoutLine
+		l.Quantity = it.Quantity
+		if it.BundleID != nil {
+			var isActive bool
+			err := db.QueryRow("SELECT price, is_active FROM bundles WHERE id = ?", *it.BundleID).
+				Scan(&l.Price, &isActive)
+			if err == sql.ErrNoRows || (err == nil && !isActive) {
+				respondError(w, http.StatusBadRequest, "bundle tidak ditemukan atau tidak aktif")
+				return
+			} else if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			available, err := bundleAvailableStock(*it.BundleID)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if available < it.Quantity {
+				respondError(w, http.StatusBadRequest, "stock komponen bundle tidak mencukupi")
+				return
+			}
+			l.BundleID = sql.NullInt64{Int64: *it.BundleID, Valid: true}
+		} else {
+			if it.ProductID == nil {
+				respondError(w, http.StatusBadRequest, "product_id atau bundle_id wajib diisi")
+				return
+			}
+			price, stock, isPreorder, err := resolveItemPrice(*it.ProductID, it.VariantID)
+			if err == errVariantRequired {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			} else if err == sql.ErrNoRows {
+				respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+				return
+			} else if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			var isDigital bool
+			if err := db.QueryRow("SELECT is_digital FROM products WHERE id = ?", *it.ProductID).
+				Scan(&isDigital); err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if stock < it.Quantity && !isPreorder && !isDigital {
+				respondError(w, http.StatusBadRequest, "stock tidak mencukupi")
+				return
+			}
+			l.Price = price
+			l.Preorder = isPreorder
+			l.Digital = isDigital
+			l.ProductID = sql.NullInt64{Int64: *it.ProductID, Valid: true}
+			if it.VariantID != nil {
+				l.VariantID = sql.NullInt64{Int64: *it.VariantID, Valid: true}
+			}
+		}
+		total += l.Price * float64(l.Quantity)
+		lines = append(lines, l)
+	}
+
+	var promoLines []promoLine
+	for _, l := range lines {
+		if l.ProductID.Valid {
+			promoLines = append(promoLines, promoLine{
+				ProductID: l.ProductID.Int64, Quantity: l.Quantity, Price: l.Price})
+		}
+	}
+	promotions, err := evaluatePromotions(promoLines)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	subtotal := total
+	for _, a := range promotions {
+		total -= a.Discount
+	}
+
+	if req.PaymentMethod == "cod" {
+		if msg := codEligibilityError(total, req.DeliveryArea); msg != "" {
+			respondError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
+	cs := &checkoutSummary{
+		Lines:      lines,
+		Subtotal:   subtotal,
+		Promotions: promotions,
+		Total:      total,
+	}
+	// Staff orders get the full reservation window regardless of the
+	// customer's hearts.
+	expiration := nowUTC().Add(24 * time.Hour)
+	var orderID int64
+	err = WithTx(func(tx *sql.Tx) error {
+		var err error
+		orderID, err = writeOrder(tx, req.UserID, cs, req.PaymentMethod, sql.NullInt64{}, expiration)
+		return err
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	createNotification(req.UserID, "Order dibuatkan",
+		"Tim kami membuatkan order untuk kamu. Segera selesaikan pembayarannya ya!")
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"id":               orderID,
+		"user_id":          req.UserID,
+		"total_price":      cs.Total,
+		"status":           "waitToBuy",
+		"timer_expiration": expiration,
+	})
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func registerAdminUserRoutes(r *Router) {
+	r.GET("/api/v1/admin/users", RoleMiddleware("admin")(ListUsers))
+	r.GET("/api/v1/admin/users/:id", RoleMiddleware("admin")(GetUserByID))
+	r.PATCH("/api/v1/admin/users/:id", RoleMiddleware("admin")(AdminUpdateUser))
+	r.POST("/api/v1/admin/users/:id/ban", RoleMiddleware("admin")(BanUser))
+	r.POST("/api/v1/admin/users/:id/unban", RoleMiddleware("admin")(UnbanUser))
+}
+
+// adminUser is the directory view of an account, including moderation fields
+// the public profile never exposes.
+type adminUser struct {
+	ID         int64   `json:"id"`
+	Username   string  `json:"username"`
+	Email      string  `json:"email"`
+	Phone      *string `json:"phone"`
+	Hearts     int     `json:"hearts"`
+	IsVerified bool    `json:"is_verified"`
+	IsBanned   bool    `json:"is_banned"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+const adminUserColumns = "id, username, email, phone, hearts, is_verified, is_banned, created_at"
+
+func scanAdminUser(row interface{ Scan(...any) error }) (adminUser, error) {
+	var (
+		u       adminUser
+		phone   sql.NullString
+		created time.Time
+	)
+	err := row.Scan(&u.ID, &u.Username, &u.Email, &phone, &u.Hearts,
+		&u.IsVerified, &u.IsBanned, &created)
+	if err != nil {
+		return u, err
+	}
+	if phone.Valid {
+		u.Phone = &phone.String
+	}
+	u.CreatedAt = displayTime(created)
+	return u, nil
+}
+
+// ListUsers is the admin user directory: ?q= searches username and email,
+// ?hearts= and ?registered_from=/registered_to= (YYYY-MM-DD) narrow the set,
+// ?banned=true shows only suspended accounts. Keyset pagination via ?limit=
+// and ?cursor= like the other listings.
+func ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := "SELECT " + adminUserColumns + " FROM users WHERE deleted_at IS NULL"
+	args := []any{}
+	if q := r.URL.Query().Get("q"); q != "" {
+		query += " AND (username LIKE ? OR email LIKE ?)"
+		like := "%" + q + "%"
+		args = append(args, like, like)
+	}
+	if h := r.URL.Query().Get("hearts"); h != "" {
+		n, err := strconv.Atoi(h)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "hearts tidak valid")
+			return
+		}
+		query += " AND hearts = ?"
+		args = append(args, n)
+	}
+	if from := r.URL.Query().Get("registered_from"); from != "" {
+		t, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "registered_from harus format YYYY-MM-DD")
+			return
+		}
+		query += " AND created_at >= ?"
+		args = append(args, t)
+	}
+	if to := r.URL.Query().Get("registered_to"); to != "" {
+		t, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "registered_to harus format YYYY-MM-DD")
+			return
+		}
+		query += " AND created_at < ?"
+		args = append(args, t.Add(24*time.Hour))
+	}
+	if r.URL.Query().Get("banned") == "true" {
+		query += " AND is_banned = 1"
+	}
+	k := keysetParams(r)
+	if k.Enabled {
+		if k.Cursor > 0 {
+			query += " AND id < ?"
+			args = append(args, k.Cursor)
+		}
+		query += " ORDER BY id DESC LIMIT ?"
+		args = append(args, k.Limit)
+	} else {
+		query += " ORDER BY id DESC LIMIT 50"
+	}
+	rows, err := readOnlyDB().Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	users := []adminUser{}
+	for rows.Next() {
+		u, err := scanAdminUser(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		users = append(users, u)
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(users) > 0 {
+			lastID = users[len(users)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       users,
+			"next_cursor": nextCursor(lastID, len(users), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, users)
+}
+
+// GetUserByID returns one account in the directory view.
+func GetUserByID(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	u, err := scanAdminUser(readOnlyDB().QueryRow(
+		"SELECT "+adminUserColumns+" FROM users WHERE id = ? AND deleted_at IS NULL", id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "user tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, u)
+}
+
+// AdminUpdateUser fixes up an account on a customer's behalf: username,
+// email, or phone. Hearts go through the hearts endpoints so every change
+// lands in the ledger; bans go through the ban endpoints.
+func AdminUpdateUser(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Username *string `json:"username"`
+		Email    *string `json:"email"`
+		Phone    *string `json:"phone"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Username == nil && req.Email == nil && req.Phone == nil {
+		respondError(w, http.StatusBadRequest, "tidak ada field yang diubah")
+		return
+	}
+	sets := []string{}
+	args := []any{}
+	if req.Username != nil {
+		if *req.Username == "" {
+			respondError(w, http.StatusBadRequest, "username tidak boleh kosong")
+			return
+		}
+		sets = append(sets, "username = ?")
+		args = append(args, *req.Username)
+	}
+	if req.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*req.Email))
+		if email == "" {
+			respondError(w, http.StatusBadRequest, "email tidak boleh kosong")
+			return
+		}
+		sets = append(sets, "email = ?")
+		args = append(args, email)
+	}
+	if req.Phone != nil {
+		sets = append(sets, "phone = ?")
+		if *req.Phone == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *req.Phone)
+		}
+	}
+	args = append(args, id)
+	res, err := db.Exec("UPDATE users SET "+strings.Join(sets, ", ")+" WHERE id = ? AND deleted_at IS NULL", args...)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "username, email, atau nomor telepon sudah dipakai")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "user tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "user berhasil diperbarui")
+}
+
+// isUserBanned reports whether the account has been suspended; tokens of a
+// banned user keep verifying, so the middleware asks here on every request.
+func isUserBanned(userID int64) (bool, error) {
+	var banned bool
+	err := db.QueryRow("SELECT is_banned FROM users WHERE id = ?", userID).Scan(&banned)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return banned, err
+}
+
+// BanUser suspends an account: marks it banned, cancels its unpaid orders,
+// and releases every stock hold so nothing stays locked behind the ban.
+func BanUser(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec("UPDATE users SET is_banned = 1, banned_at = ? WHERE id = ?",
+			nowUTC(), id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return sql.ErrNoRows
+		}
+		rows, err := tx.Query("SELECT id FROM orders WHERE user_id = ? AND status = 'waitToBuy'", id)
+		if err != nil {
+			return err
+		}
+		var orderIDs []int64
+		for rows.Next() {
+			var orderID int64
+			if err := rows.Scan(&orderID); err != nil {
+				rows.Close()
+				return err
+			}
+			orderIDs = append(orderIDs, orderID)
+		}
+		rows.Close()
+		for _, orderID := range orderIDs {
+			if _, err := tx.Exec(
+				"UPDATE orders SET status = 'cancelled' WHERE id = ?", orderID); err != nil {
+				return err
+			}
+			if err := releaseReservationsForOrder(tx, orderID); err != nil {
+				return err
+			}
+		}
+		// Standalone holds (reservations not yet attached to an order).
+		holdRows, err := tx.Query(
+			`SELECT id, product_id, variant_id, quantity FROM stock_reservations
+			 WHERE user_id = ? AND order_id IS NULL AND status = 'active'`, id)
+		if err != nil {
+			return err
+		}
+		type held struct {
+			id, productID int64
+			variantID     sql.NullInt64
+			quantity      int
+		}
+		var holds []held
+		for holdRows.Next() {
+			var h held
+			if err := holdRows.Scan(&h.id, &h.productID, &h.variantID, &h.quantity); err != nil {
+				holdRows.Close()
+				return err
+			}
+			holds = append(holds, h)
+		}
+		holdRows.Close()
+		for _, h := range holds {
+			if err := restoreStock(tx, h.productID, h.variantID, h.quantity); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(
+				"UPDATE stock_reservations SET status = 'cancelled', status_changed_at = ? WHERE id = ?", nowUTC(), h.id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "user tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "user berhasil ditangguhkan")
+}
+
+// UnbanUser lifts a suspension.
+func UnbanUser(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("UPDATE users SET is_banned = 0, banned_at = NULL WHERE id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "user tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "penangguhan user dicabut")
+}
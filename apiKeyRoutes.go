@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func registerApiKeyRoutes(r *Router) {
+	r.GET("/api/v1/admin/api-keys", RoleMiddleware("admin")(GetApiKeys))
+	r.POST("/api/v1/admin/api-keys", RoleMiddleware("admin")(CreateApiKey))
+	r.DELETE("/api/v1/admin/api-keys/:id", RoleMiddleware("admin")(RevokeApiKey))
+
+	// Server-to-server surface: POS boxes and cron jobs hit these with an
+	// X-API-Key header instead of a user JWT.
+	r.GET("/api/v1/integrations/products", ApiKeyMiddleware("read:products")(GetAllProducts))
+	r.POST("/api/v1/integrations/stock-adjust", ApiKeyMiddleware("manage:stock")(IntegrationAdjustStock))
+}
+
+// apiKeyScopes is the closed set of permissions a key can carry.
+var apiKeyScopes = map[string]bool{
+	"read:products": true,
+	"manage:stock":  true,
+}
+
+// ApiKeyMiddleware authenticates the X-API-Key header and checks that the key
+// carries the required scope. Key lookups go by hash, so the plaintext only
+// ever exists in the create response.
+func ApiKeyMiddleware(scope string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get("X-API-Key")
+			if key == "" {
+				respondError(w, http.StatusUnauthorized, "header X-API-Key tidak ditemukan")
+				return
+			}
+			var (
+				id     int64
+				scopes string
+			)
+			err := db.QueryRow(
+				"SELECT id, scopes FROM api_keys WHERE key_hash = ? AND revoked_at IS NULL",
+				hashRefreshToken(key)).Scan(&id, &scopes)
+			if err == sql.ErrNoRows {
+				respondError(w, http.StatusUnauthorized, "api key tidak valid")
+				return
+			} else if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if !hasScope(scopes, scope) {
+				respondError(w, http.StatusForbidden, "api key tidak punya scope "+scope)
+				return
+			}
+			if _, err := db.Exec("UPDATE api_keys SET last_used_at = ? WHERE id = ?",
+				nowUTC(), id); err != nil {
+				log.Printf("gagal update last_used_at api key %d: %v", id, err)
+			}
+			next(w, r)
+		}
+	}
+}
+
+func hasScope(scopes, want string) bool {
+	for _, s := range strings.Split(scopes, ",") {
+		if strings.TrimSpace(s) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// ApiKey is an integration credential; the plaintext key is never stored.
+type ApiKey struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Scopes     string  `json:"scopes"`
+	CreatedAt  string  `json:"created_at"`
+	LastUsedAt *string `json:"last_used_at"`
+}
+
+// GetApiKeys lists all keys, including revoked ones, without their secrets.
+func GetApiKeys(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		"SELECT id, name, scopes, created_at, last_used_at FROM api_keys WHERE revoked_at IS NULL ORDER BY id")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	keys := []ApiKey{}
+	for rows.Next() {
+		var (
+			k        ApiKey
+			created  time.Time
+			lastUsed sql.NullTime
+		)
+		if err := rows.Scan(&k.ID, &k.Name, &k.Scopes, &created, &lastUsed); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		k.CreatedAt = displayTime(created)
+		if lastUsed.Valid {
+			s := displayTime(lastUsed.Time)
+			k.LastUsedAt = &s
+		}
+		keys = append(keys, k)
+	}
+	respondJSON(w, http.StatusOK, keys)
+}
+
+// CreateApiKey mints a key and returns the plaintext exactly once.
+func CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name   string   `json:"name"`
+		Scopes []string `json:"scopes"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Name == "" || len(req.Scopes) == 0 {
+		respondError(w, http.StatusBadRequest, "name dan scopes wajib diisi")
+		return
+	}
+	for _, s := range req.Scopes {
+		if !apiKeyScopes[s] {
+			respondError(w, http.StatusBadRequest, "scope tidak dikenal: "+s)
+			return
+		}
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	key := hex.EncodeToString(raw)
+	res, err := db.Exec(
+		"INSERT INTO api_keys (name, key_hash, scopes, created_at) VALUES (?, ?, ?, ?)",
+		req.Name, hashRefreshToken(key), strings.Join(req.Scopes, ","), nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"id":      id,
+		"api_key": key,
+		"message": "simpan key ini sekarang, tidak akan ditampilkan lagi",
+	})
+}
+
+// RevokeApiKey disables a key immediately.
+func RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("UPDATE api_keys SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL",
+		nowUTC(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "api key tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "api key dicabut")
+}
+
+// IntegrationAdjustStock lets an external system shift stock; the movement is
+// recorded with actor 0 since no human account is behind the key.
+func IntegrationAdjustStock(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProductID int64  `json:"product_id"`
+		VariantID *int64 `json:"variant_id"`
+		Delta     int    `json:"delta"`
+		Reason    string `json:"reason"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.ProductID == 0 || req.Delta == 0 {
+		respondError(w, http.StatusBadRequest, "product_id dan delta wajib diisi")
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = "integration"
+	}
+	if err := applyStockDelta(req.ProductID, req.VariantID, req.Delta, req.Reason, 0, 0); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "stok berhasil disesuaikan")
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerAuditLogRoutes(r *Router) {
+	r.GET("/api/v1/admin/audit-logs", RoleMiddleware("admin")(GetAuditLogs))
+}
+
+// AuditLog is one recorded staff mutation with the entity snapshots around it.
+type AuditLog struct {
+	ID        int64           `json:"id"`
+	ActorID   int64           `json:"actor_id"`
+	ActorRole string          `json:"actor_role"`
+	Action    string          `json:"action"`
+	Entity    string          `json:"entity"`
+	EntityID  int64           `json:"entity_id"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt string          `json:"created_at"`
+}
+
+// recordAudit writes one audit row; before/after may be nil for creates and
+// deletes. Failures are logged, never surfaced — same contract as the stock
+// ledger.
+func recordAudit(r *http.Request, action, entity string, entityID int64, before, after any) {
+	var (
+		actorID int64
+		role    string
+	)
+	if claims := currentClaims(r); claims != nil {
+		actorID, role = claims.ID, claims.Role
+	}
+	recordAuditAs(actorID, role, action, entity, entityID, before, after)
+}
+
+// recordAuditAs is the request-less variant for code paths like workers and
+// stock helpers that already know the actor.
+func recordAuditAs(actorID int64, role, action, entity string, entityID int64, before, after any) {
+	marshal := func(v any) []byte {
+		if v == nil {
+			return nil
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			log.Printf("gagal serialisasi snapshot audit %s %d: %v", entity, entityID, err)
+			return nil
+		}
+		return raw
+	}
+	_, err := db.Exec(
+		`INSERT INTO audit_logs (actor_id, actor_role, action, entity, entity_id, before_json, after_json, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		actorID, role, action, entity, entityID, marshal(before), marshal(after), nowUTC())
+	if err != nil {
+		log.Printf("gagal mencatat audit log %s %s %d: %v", action, entity, entityID, err)
+	}
+}
+
+// productSnapshot loads the full product row for a before/after snapshot;
+// errors fall back to nil so auditing never blocks the mutation itself.
+func productSnapshot(id int64) any {
+	p, err := scanProduct(db.QueryRow(
+		"SELECT "+productColumns+" FROM products WHERE id = ?", id))
+	if err != nil {
+		return nil
+	}
+	return p
+}
+
+// variantSnapshot loads the full variant row for a before/after snapshot,
+// with the same nil fallback as productSnapshot.
+func variantSnapshot(id int64) any {
+	v, err := fetchVariant(id)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// GetAuditLogs lists audit entries newest first with keyset pagination and
+// optional ?entity= filtering.
+func GetAuditLogs(w http.ResponseWriter, r *http.Request) {
+	k := keysetParams(r)
+	query := `SELECT id, actor_id, actor_role, action, entity, entity_id, before_json, after_json, created_at
+	          FROM audit_logs WHERE 1=1`
+	args := []any{}
+	if entity := r.URL.Query().Get("entity"); entity != "" {
+		query += " AND entity = ?"
+		args = append(args, entity)
+	}
+	if k.Enabled && k.Cursor > 0 {
+		query += " AND id < ?"
+		args = append(args, k.Cursor)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	if k.Enabled {
+		args = append(args, k.Limit)
+	} else {
+		args = append(args, 50)
+	}
+	rows, err := readOnlyDB().Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	logs := []AuditLog{}
+	for rows.Next() {
+		var (
+			entry         AuditLog
+			before, after []byte
+			createdAt     time.Time
+		)
+		if err := rows.Scan(&entry.ID, &entry.ActorID, &entry.ActorRole, &entry.Action,
+			&entry.Entity, &entry.EntityID, &before, &after, &createdAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		entry.Before = before
+		entry.After = after
+		entry.CreatedAt = displayTime(createdAt)
+		logs = append(logs, entry)
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(logs) > 0 {
+			lastID = logs[len(logs)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       logs,
+			"next_cursor": nextCursor(lastID, len(logs), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, logs)
+}
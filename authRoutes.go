@@ -0,0 +1,279 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+func registerAuthRoutes(r *Router) {
+	r.POST("/api/v1/register", RateLimitMiddleware(handleUserRegister))
+	r.POST("/api/v1/login", RateLimitMiddleware(handleLoginWithRole))
+	r.POST("/api/v1/admin/employees", RoleMiddleware("admin")(handleEmployeeRegister))
+}
+
+type registerRequest struct {
+	Username     string  `json:"username"`
+	Email        string  `json:"email"`
+	Phone        *string `json:"phone"`
+	Password     string  `json:"password"`
+	CaptchaToken string  `json:"captcha_token"`
+	ReferralCode string  `json:"referral_code"`
+}
+
+func handleUserRegister(w http.ResponseWriter, r *http.Request) {
+	if !requireAuthAttempt(w, r) {
+		return
+	}
+	var req registerRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if !requireCaptcha(w, r, req.CaptchaToken) {
+		return
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if req.Username == "" || req.Email == "" {
+		respondError(w, http.StatusBadRequest, "username dan email wajib diisi")
+		return
+	}
+	if !requireValidPassword(w, req.Password) {
+		return
+	}
+	// The per-table unique constraint can't see admins or employees, so the
+	// cross-table check has to happen here.
+	if taken, err := emailInUse(req.Email); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if taken {
+		respondError(w, http.StatusConflict, "email sudah terdaftar")
+		return
+	}
+	hashed, err := hashPassword(req.Password)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// The unique constraints on email/username are the source of truth; a
+	// check-then-insert would lose the race under concurrent signups.
+	res, err := db.Exec(
+		"INSERT INTO users (username, email, phone, password, hearts, is_verified, created_at) VALUES (?, ?, ?, ?, 3, 0, ?)",
+		req.Username, req.Email, req.Phone, hashed, nowUTC())
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "email atau username sudah terdaftar")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if req.ReferralCode != "" {
+		if newID, err := res.LastInsertId(); err == nil {
+			linkReferral(newID, req.ReferralCode)
+		}
+	}
+	sendVerificationMail(req.Email)
+	respondMessage(w, http.StatusCreated, "registrasi berhasil, cek email untuk verifikasi")
+}
+
+// isDuplicateKeyError reports whether err is a MySQL duplicate-key violation
+// (1062) on an INSERT.
+func isDuplicateKeyError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Error 1062") || strings.Contains(msg, "Duplicate entry")
+}
+
+type employeeRegisterRequest struct {
+	registerRequest
+	Role string `json:"role"`
+}
+
+func handleEmployeeRegister(w http.ResponseWriter, r *http.Request) {
+	var req employeeRegisterRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Role != "stocker" && req.Role != "cashier" && req.Role != "driver" {
+		respondError(w, http.StatusBadRequest, "role employee harus stocker, cashier, atau driver")
+		return
+	}
+	if !requireValidPassword(w, req.Password) {
+		return
+	}
+	if taken, err := emailInUse(req.Email); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	} else if taken {
+		respondError(w, http.StatusConflict, "email sudah terdaftar")
+		return
+	}
+	hashed, err := hashPassword(req.Password)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	_, err = db.Exec("INSERT INTO employees (username, email, password, role) VALUES (?, ?, ?, ?)",
+		req.Username, req.Email, hashed, req.Role)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "email sudah terdaftar")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusCreated, "employee berhasil dibuat")
+}
+
+type loginRequest struct {
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	CaptchaToken string `json:"captcha_token"`
+	// TOTPCode is required when the staff account has 2FA enabled; a
+	// recovery code is accepted in its place.
+	TOTPCode string `json:"totp_code"`
+	// RememberMe stretches the refresh token to the remember-me window.
+	RememberMe bool `json:"remember_me"`
+}
+
+// account is one credential row regardless of which table it lives in.
+type account struct {
+	ID       int64
+	Password string
+	Role     string
+}
+
+var errAmbiguousEmail = errors.New("email terdaftar di lebih dari satu tipe akun, hubungi admin")
+
+// findAccountByEmail searches users, admins, and employees for the email and
+// derives the role server-side. An email living in more than one table is an
+// integrity problem and is reported instead of silently picking one.
+func findAccountByEmail(email string) (*account, error) {
+	var found []account
+	var a account
+	err := db.QueryRow("SELECT id, password FROM users WHERE email = ?", email).
+		Scan(&a.ID, &a.Password)
+	if err == nil {
+		a.Role = "user"
+		found = append(found, a)
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+	err = db.QueryRow("SELECT id, password FROM admins WHERE email = ?", email).
+		Scan(&a.ID, &a.Password)
+	if err == nil {
+		a.Role = "admin"
+		found = append(found, a)
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+	err = db.QueryRow("SELECT id, password, role FROM employees WHERE email = ?", email).
+		Scan(&a.ID, &a.Password, &a.Role)
+	if err == nil {
+		found = append(found, a)
+	} else if err != sql.ErrNoRows {
+		return nil, err
+	}
+	switch len(found) {
+	case 0:
+		return nil, sql.ErrNoRows
+	case 1:
+		return &found[0], nil
+	}
+	return nil, errAmbiguousEmail
+}
+
+// emailInUse reports whether any account type already holds the email, so new
+// registrations can't create a second identity for it.
+func emailInUse(email string) (bool, error) {
+	_, err := findAccountByEmail(email)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err == errAmbiguousEmail {
+		return true, nil
+	}
+	return err == nil, err
+}
+
+// handleLoginWithRole authenticates by email alone; the role comes from
+// whichever table holds the account, never from the client.
+func handleLoginWithRole(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if !requireLoginAttempt(w, r, req.Email) {
+		return
+	}
+	if !requireCaptcha(w, r, req.CaptchaToken) {
+		return
+	}
+	acct, err := findAccountByEmail(req.Email)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusUnauthorized, "email atau password salah")
+		return
+	} else if err == errAmbiguousEmail {
+		respondError(w, http.StatusConflict, err.Error())
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, role := acct.ID, acct.Role
+	if !checkPassword(acct.Password, req.Password) {
+		respondError(w, http.StatusUnauthorized, "email atau password salah")
+		return
+	}
+	if role != "user" {
+		enabled, err := twoFactorEnabled(id, role)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if enabled {
+			if req.TOTPCode == "" {
+				respondError(w, http.StatusUnauthorized, "akun ini memerlukan kode TOTP")
+				return
+			}
+			ok, err := checkTwoFactor(id, role, req.TOTPCode)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if !ok {
+				respondError(w, http.StatusUnauthorized, "kode TOTP salah")
+				return
+			}
+		}
+	}
+	clearLoginThrottle(r, req.Email)
+	recordLogin(id, role, r)
+	respondWithToken(w, r, id, role, req.RememberMe)
+}
+
+func respondWithToken(w http.ResponseWriter, r *http.Request, id int64, role string, remember bool) {
+	token, err := generateToken(id, role)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	refresh, err := issueRefreshToken(id, role, r, remember)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if config.CookieAuth {
+		setAuthCookies(w, token)
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"token":         token,
+		"refresh_token": refresh,
+		"role":          role,
+	})
+}
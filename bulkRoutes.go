@@ -0,0 +1,286 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+)
+
+func registerBulkRoutes(r *Router) {
+	r.POST("/api/v1/admin/products/bulk-delete", RoleMiddleware("admin")(BulkDeleteProducts))
+	r.POST("/api/v1/admin/images/bulk-delete", RoleMiddleware("admin")(BulkDeleteProductImages))
+	r.POST("/api/v1/admin/notifications/bulk-delete", RoleMiddleware("admin")(BulkDeleteNotifications))
+	r.PATCH("/api/v1/admin/products/bulk-price", RoleMiddleware("admin")(BulkUpdatePrices))
+}
+
+// bulkResult reports what happened to one id of a bulk operation.
+type bulkResult struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func decodeBulkIDs(w http.ResponseWriter, r *http.Request) ([]int64, bool) {
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := decodeBody(r, &req); err != nil || len(req.IDs) == 0 {
+		respondError(w, http.StatusBadRequest, "ids wajib diisi")
+		return nil, false
+	}
+	return req.IDs, true
+}
+
+// BulkDeleteProducts removes a list of products with their dependent rows,
+// one transaction per product so a failure only skips that id.
+func BulkDeleteProducts(w http.ResponseWriter, r *http.Request) {
+	ids, ok := decodeBulkIDs(w, r)
+	if !ok {
+		return
+	}
+	results := make([]bulkResult, 0, len(ids))
+	for _, id := range ids {
+		var ordered int
+		if err := db.QueryRow("SELECT COUNT(*) FROM order_items WHERE product_id = ?", id).
+			Scan(&ordered); err != nil {
+			results = append(results, bulkResult{ID: id, Status: "failed", Error: err.Error()})
+			continue
+		}
+		if ordered > 0 {
+			results = append(results, bulkResult{ID: id, Status: "skipped",
+				Error: "produk masih direferensikan oleh order"})
+			continue
+		}
+		err := WithTx(func(tx *sql.Tx) error {
+			for _, stmt := range []string{
+				"DELETE FROM cart_items WHERE product_id = ?",
+				"DELETE FROM wishlists WHERE product_id = ?",
+				"DELETE FROM product_variants WHERE product_id = ?",
+				"DELETE FROM product_images WHERE product_id = ?",
+				"DELETE FROM product_files WHERE product_id = ?",
+			} {
+				if _, err := tx.Exec(stmt, id); err != nil {
+					return err
+				}
+			}
+			res, err := tx.Exec("DELETE FROM products WHERE id = ?", id)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				return sql.ErrNoRows
+			}
+			return nil
+		})
+		switch {
+		case err == sql.ErrNoRows:
+			results = append(results, bulkResult{ID: id, Status: "not_found"})
+		case err != nil:
+			results = append(results, bulkResult{ID: id, Status: "failed", Error: err.Error()})
+		default:
+			go removeProductFromIndex(id)
+			results = append(results, bulkResult{ID: id, Status: "deleted"})
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"results": results})
+}
+
+type bulkPriceRequest struct {
+	ProductIDs []int64 `json:"product_ids"`
+	VariantIDs []int64 `json:"variant_ids"`
+	// CategoryID selects every live product in the category, on top of any
+	// explicit ids.
+	CategoryID int64 `json:"category_id"`
+	// Field is "price" (default) or "discount_price".
+	Field string `json:"field"`
+	// AdjustType is "absolute" (add value to the price) or "percent"
+	// (scale the price by value percent, e.g. -10 for a 10% cut).
+	AdjustType string  `json:"adjust_type"`
+	Value      float64 `json:"value"`
+	// DryRun computes and returns the changes without writing anything.
+	DryRun bool `json:"dry_run"`
+}
+
+// bulkPriceChange reports the old and new price of one affected row.
+type bulkPriceChange struct {
+	Entity   string  `json:"entity"`
+	ID       int64   `json:"id"`
+	OldPrice float64 `json:"old_price"`
+	NewPrice float64 `json:"new_price"`
+}
+
+var errNegativePrice = errors.New("hasil penyesuaian membuat harga negatif")
+
+func adjustedPrice(old float64, adjustType string, value float64) float64 {
+	if adjustType == "percent" {
+		return math.Round(old*(100+value)) / 100
+	}
+	return old + value
+}
+
+// collectPriceChanges loads the targeted rows of one table and computes their
+// adjusted prices.
+func collectPriceChanges(tx *sql.Tx, entity, table, where string, args []any, req *bulkPriceRequest) ([]bulkPriceChange, error) {
+	rows, err := tx.Query("SELECT id, "+req.Field+" FROM "+table+" WHERE "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	changes := []bulkPriceChange{}
+	for rows.Next() {
+		c := bulkPriceChange{Entity: entity}
+		if err := rows.Scan(&c.ID, &c.OldPrice); err != nil {
+			return nil, err
+		}
+		c.NewPrice = adjustedPrice(c.OldPrice, req.AdjustType, req.Value)
+		if c.NewPrice < 0 {
+			return nil, errNegativePrice
+		}
+		changes = append(changes, c)
+	}
+	return changes, nil
+}
+
+// BulkUpdatePrices adjusts the price (or discount price) of many products and
+// variants at once, selected by explicit ids or a category, in one
+// transaction so a partial failure never leaves the catalog half-repriced.
+// dry_run returns what would change without writing.
+func BulkUpdatePrices(w http.ResponseWriter, r *http.Request) {
+	var req bulkPriceRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Field == "" {
+		req.Field = "price"
+	}
+	if req.Field != "price" && req.Field != "discount_price" {
+		respondError(w, http.StatusBadRequest, "field harus price atau discount_price")
+		return
+	}
+	if req.AdjustType != "absolute" && req.AdjustType != "percent" {
+		respondError(w, http.StatusBadRequest, "adjust_type harus absolute atau percent")
+		return
+	}
+	if len(req.ProductIDs) == 0 && len(req.VariantIDs) == 0 && req.CategoryID == 0 {
+		respondError(w, http.StatusBadRequest, "pilih product_ids, variant_ids, atau category_id")
+		return
+	}
+	var changes []bulkPriceChange
+	err := WithTx(func(tx *sql.Tx) error {
+		var productConds []string
+		var productArgs []any
+		if len(req.ProductIDs) > 0 {
+			productConds = append(productConds, "id IN ("+strings.Repeat("?, ", len(req.ProductIDs)-1)+"?)")
+			for _, id := range req.ProductIDs {
+				productArgs = append(productArgs, id)
+			}
+		}
+		if req.CategoryID != 0 {
+			productConds = append(productConds, "category_id = ?")
+			productArgs = append(productArgs, req.CategoryID)
+		}
+		if len(productConds) > 0 {
+			productChanges, err := collectPriceChanges(tx, "product", "products",
+				"deleted_at IS NULL AND ("+strings.Join(productConds, " OR ")+")", productArgs, &req)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, productChanges...)
+		}
+		if len(req.VariantIDs) > 0 {
+			variantArgs := make([]any, len(req.VariantIDs))
+			for i, id := range req.VariantIDs {
+				variantArgs[i] = id
+			}
+			variantChanges, err := collectPriceChanges(tx, "variant", "product_variants",
+				"deleted_at IS NULL AND id IN ("+strings.Repeat("?, ", len(req.VariantIDs)-1)+"?)", variantArgs, &req)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, variantChanges...)
+		}
+		if req.DryRun {
+			return nil
+		}
+		for _, c := range changes {
+			var err error
+			if c.Entity == "product" {
+				_, err = tx.Exec("UPDATE products SET "+req.Field+" = ?, updated_at = ? WHERE id = ?",
+					c.NewPrice, nowUTC(), c.ID)
+			} else {
+				_, err = tx.Exec("UPDATE product_variants SET "+req.Field+" = ?, version = version + 1 WHERE id = ?",
+					c.NewPrice, c.ID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == errNegativePrice {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !req.DryRun {
+		for _, c := range changes {
+			if c.Entity == "product" {
+				go indexProductByID(c.ID)
+			}
+		}
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"dry_run": req.DryRun,
+		"updated": len(changes),
+		"changes": changes,
+	})
+}
+
+// BulkDeleteProductImages removes a list of product images.
+func BulkDeleteProductImages(w http.ResponseWriter, r *http.Request) {
+	ids, ok := decodeBulkIDs(w, r)
+	if !ok {
+		return
+	}
+	results := make([]bulkResult, 0, len(ids))
+	for _, id := range ids {
+		res, err := db.Exec("DELETE FROM product_images WHERE id = ?", id)
+		if err != nil {
+			results = append(results, bulkResult{ID: id, Status: "failed", Error: err.Error()})
+			continue
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			results = append(results, bulkResult{ID: id, Status: "not_found"})
+			continue
+		}
+		results = append(results, bulkResult{ID: id, Status: "deleted"})
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"results": results})
+}
+
+// BulkDeleteNotifications removes a list of notifications.
+func BulkDeleteNotifications(w http.ResponseWriter, r *http.Request) {
+	ids, ok := decodeBulkIDs(w, r)
+	if !ok {
+		return
+	}
+	results := make([]bulkResult, 0, len(ids))
+	for _, id := range ids {
+		deleted, err := softDelete("notifications", id)
+		if err != nil {
+			results = append(results, bulkResult{ID: id, Status: "failed", Error: err.Error()})
+			continue
+		}
+		if !deleted {
+			results = append(results, bulkResult{ID: id, Status: "not_found"})
+			continue
+		}
+		results = append(results, bulkResult{ID: id, Status: "deleted"})
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"results": results})
+}
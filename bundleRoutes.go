@@ -0,0 +1,339 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerBundleRoutes(r *Router) {
+	r.GET("/api/v1/bundles", GetActiveBundles)
+	r.GET("/api/v1/admin/bundles", RoleMiddleware("admin")(GetAllBundles))
+	r.POST("/api/v1/admin/bundles", RoleMiddleware("admin")(CreateBundle))
+	r.PUT("/api/v1/admin/bundles/:id", RoleMiddleware("admin")(UpdateBundle))
+	r.DELETE("/api/v1/admin/bundles/:id", RoleMiddleware("admin")(DeleteBundle))
+	r.POST("/api/v1/cart/bundles", AuthMiddleware(AddBundleToCart))
+}
+
+// Bundle is a kit of several products sold together at one price.
+type Bundle struct {
+	ID          int64        `json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Price       float64      `json:"price"`
+	IsActive    bool         `json:"is_active"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Items       []BundleItem `json:"items"`
+	Available   int          `json:"available"`
+}
+
+// BundleItem is one component of a bundle.
+type BundleItem struct {
+	ID        int64  `json:"id"`
+	BundleID  int64  `json:"bundle_id"`
+	ProductID int64  `json:"product_id"`
+	VariantID *int64 `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+func fetchBundleItems(bundleID int64) ([]BundleItem, error) {
+	rows, err := db.Query(
+		"SELECT id, bundle_id, product_id, variant_id, quantity FROM bundle_items WHERE bundle_id = ?",
+		bundleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []BundleItem{}
+	for rows.Next() {
+		var (
+			it        BundleItem
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.BundleID, &it.ProductID, &variantID, &it.Quantity); err != nil {
+			return nil, err
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// bundleAvailableStock returns how many complete bundles the scarcest component allows.
+func bundleAvailableStock(bundleID int64) (int, error) {
+	items, err := fetchBundleItems(bundleID)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+	available := -1
+	for _, it := range items {
+		var stock int
+		if it.VariantID != nil {
+			err = db.QueryRow("SELECT stock FROM product_variants WHERE id = ?", *it.VariantID).Scan(&stock)
+		} else {
+			err = db.QueryRow("SELECT stock FROM products WHERE id = ?", it.ProductID).Scan(&stock)
+		}
+		if err != nil {
+			return 0, err
+		}
+		sets := stock / it.Quantity
+		if available == -1 || sets < available {
+			available = sets
+		}
+	}
+	return available, nil
+}
+
+func listBundles(w http.ResponseWriter, activeOnly bool) {
+	query := "SELECT id, name, description, price, is_active, created_at FROM bundles"
+	if activeOnly {
+		query += " WHERE is_active = 1"
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	bundles := []Bundle{}
+	for rows.Next() {
+		var b Bundle
+		if err := rows.Scan(&b.ID, &b.Name, &b.Description, &b.Price, &b.IsActive, &b.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		bundles = append(bundles, b)
+	}
+	for i := range bundles {
+		bundles[i].Items, err = fetchBundleItems(bundles[i].ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		bundles[i].Available, err = bundleAvailableStock(bundles[i].ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	respondJSON(w, http.StatusOK, bundles)
+}
+
+// GetActiveBundles lists active bundles for the storefront.
+func GetActiveBundles(w http.ResponseWriter, r *http.Request) {
+	listBundles(w, true)
+}
+
+// GetAllBundles lists every bundle for admins.
+func GetAllBundles(w http.ResponseWriter, r *http.Request) {
+	listBundles(w, false)
+}
+
+type bundleRequest struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	IsActive    bool    `json:"is_active"`
+	Items       []struct {
+		ProductID int64  `json:"product_id"`
+		VariantID *int64 `json:"variant_id"`
+		Quantity  int    `json:"quantity"`
+	} `json:"items"`
+}
+
+func (req *bundleRequest) validate() string {
+	if req.Name == "" {
+		return "nama bundle wajib diisi"
+	}
+	if req.Price <= 0 {
+		return "harga bundle harus lebih dari nol"
+	}
+	if len(req.Items) < 2 {
+		return "bundle minimal terdiri dari 2 komponen"
+	}
+	for _, it := range req.Items {
+		if it.Quantity <= 0 {
+			return "quantity komponen minimal 1"
+		}
+	}
+	return ""
+}
+
+func insertBundleItems(q dbtx, bundleID int64, req *bundleRequest) error {
+	for _, it := range req.Items {
+		if _, err := q.Exec(
+			"INSERT INTO bundle_items (bundle_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)",
+			bundleID, it.ProductID, it.VariantID, it.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateBundle inserts a bundle with its components.
+func CreateBundle(w http.ResponseWriter, r *http.Request) {
+	var req bundleRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	for _, it := range req.Items {
+		var exists int
+		if err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", it.ProductID).Scan(&exists); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if exists == 0 {
+			respondError(w, http.StatusNotFound, "produk komponen tidak ditemukan")
+			return
+		}
+	}
+	var id int64
+	err := WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"INSERT INTO bundles (name, description, price, is_active, created_at) VALUES (?, ?, ?, ?, ?)",
+			req.Name, req.Description, req.Price, req.IsActive, nowUTC())
+		if err != nil {
+			return err
+		}
+		id, _ = res.LastInsertId()
+		return insertBundleItems(tx, id, &req)
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "bundle berhasil dibuat"})
+}
+
+// UpdateBundle overwrites a bundle and replaces its components.
+func UpdateBundle(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req bundleRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"UPDATE bundles SET name = ?, description = ?, price = ?, is_active = ? WHERE id = ?",
+			req.Name, req.Description, req.Price, req.IsActive, id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return sql.ErrNoRows
+		}
+		if _, err := tx.Exec("DELETE FROM bundle_items WHERE bundle_id = ?", id); err != nil {
+			return err
+		}
+		return insertBundleItems(tx, id, &req)
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "bundle tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "bundle berhasil diupdate")
+}
+
+// DeleteBundle removes a bundle and its components.
+func DeleteBundle(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	db.Exec("DELETE FROM bundle_items WHERE bundle_id = ?", id)
+	res, err := db.Exec("DELETE FROM bundles WHERE id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "bundle tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "bundle berhasil dihapus")
+}
+
+// AddBundleToCart puts a bundle line into the cart, billed at the bundle price.
+func AddBundleToCart(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		BundleID int64 `json:"bundle_id"`
+		Quantity int   `json:"quantity"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Quantity <= 0 {
+		respondError(w, http.StatusBadRequest, "quantity minimal 1")
+		return
+	}
+	var (
+		price    float64
+		isActive bool
+	)
+	err := db.QueryRow("SELECT price, is_active FROM bundles WHERE id = ?", req.BundleID).Scan(&price, &isActive)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "bundle tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !isActive {
+		respondError(w, http.StatusBadRequest, "bundle sedang tidak aktif")
+		return
+	}
+	available, err := bundleAvailableStock(req.BundleID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if available < req.Quantity {
+		respondError(w, http.StatusBadRequest, "stock komponen bundle tidak mencukupi")
+		return
+	}
+	cartID, err := getOrCreateCart(currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var id int64
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"INSERT INTO cart_items (cart_id, bundle_id, quantity, price) VALUES (?, ?, ?, ?)",
+			cartID, req.BundleID, req.Quantity, price)
+		if err != nil {
+			return err
+		}
+		id, _ = res.LastInsertId()
+		return AddToCartTotalPrice(tx, cartID, price*float64(req.Quantity))
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "bundle ditambahkan ke cart"})
+}
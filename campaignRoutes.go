@@ -0,0 +1,237 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerCampaignRoutes(r *Router) {
+	r.GET("/api/v1/admin/campaigns", RoleMiddleware("admin")(GetCampaigns))
+	r.POST("/api/v1/admin/campaigns", RoleMiddleware("admin")(CreateCampaign))
+	r.PUT("/api/v1/admin/campaigns/:id", RoleMiddleware("admin")(UpdateCampaign))
+	r.DELETE("/api/v1/admin/campaigns/:id", RoleMiddleware("admin")(DeleteCampaign))
+}
+
+// Campaign discounts every product of a category by a percentage for a date
+// range. It is computed into effective_price at read time, so product price
+// columns are never mutated and the discount disappears on its own when the
+// range ends.
+type Campaign struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	CategoryID int64     `json:"category_id"`
+	Percent    float64   `json:"percent"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+const campaignColumns = "id, name, category_id, percent, starts_at, ends_at, created_at"
+
+func scanCampaign(row interface{ Scan(...any) error }) (Campaign, error) {
+	var c Campaign
+	err := row.Scan(&c.ID, &c.Name, &c.CategoryID, &c.Percent, &c.StartsAt, &c.EndsAt, &c.CreatedAt)
+	return c, err
+}
+
+// activeCampaignPercent returns the largest active campaign percentage for a
+// category, or 0 when none is running.
+func activeCampaignPercent(categoryID int64) float64 {
+	var percent sql.NullFloat64
+	err := db.QueryRow(
+		`SELECT MAX(percent) FROM category_campaigns
+		 WHERE category_id = ? AND starts_at <= ? AND ends_at >= ?`,
+		categoryID, nowUTC(), nowUTC()).Scan(&percent)
+	if err != nil {
+		log.Printf("gagal membaca campaign kategori %d: %v", categoryID, err)
+		return 0
+	}
+	return percent.Float64
+}
+
+// campaignPrice lowers currentPrice to the campaign price computed from the
+// list price when a category campaign beats the product's own discount.
+func campaignPrice(categoryID int64, listPrice, currentPrice float64) (float64, float64) {
+	percent := activeCampaignPercent(categoryID)
+	if percent <= 0 {
+		return currentPrice, 0
+	}
+	discounted := listPrice * (1 - percent/100)
+	if discounted >= currentPrice {
+		return currentPrice, 0
+	}
+	return discounted, percent
+}
+
+type campaignRequest struct {
+	Name       string  `json:"name"`
+	CategoryID int64   `json:"category_id"`
+	Percent    float64 `json:"percent"`
+	StartsAt   string  `json:"starts_at"`
+	EndsAt     string  `json:"ends_at"`
+}
+
+// parse validates the request and returns the parsed date range.
+func (req *campaignRequest) parse() (start, end time.Time, msg string) {
+	if req.Name == "" || req.CategoryID == 0 {
+		return start, end, "name dan category_id wajib diisi"
+	}
+	if req.Percent <= 0 || req.Percent >= 100 {
+		return start, end, "percent harus di antara 0 dan 100"
+	}
+	var err error
+	if start, err = time.Parse(time.RFC3339, req.StartsAt); err != nil {
+		return start, end, "starts_at harus format RFC3339"
+	}
+	if end, err = time.Parse(time.RFC3339, req.EndsAt); err != nil {
+		return start, end, "ends_at harus format RFC3339"
+	}
+	if !end.After(start) {
+		return start, end, "ends_at harus setelah starts_at"
+	}
+	return start.UTC(), end.UTC(), ""
+}
+
+// campaignOverlaps reports whether another campaign on the same category
+// overlaps the range; excludeID skips the campaign being updated.
+func campaignOverlaps(categoryID int64, start, end time.Time, excludeID int64) (bool, error) {
+	var count int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM category_campaigns
+		 WHERE category_id = ? AND id <> ? AND starts_at <= ? AND ends_at >= ?`,
+		categoryID, excludeID, end, start).Scan(&count)
+	return count > 0, err
+}
+
+// GetCampaigns lists campaigns, newest range first.
+func GetCampaigns(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT " + campaignColumns + " FROM category_campaigns ORDER BY starts_at DESC")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	campaigns := []Campaign{}
+	for rows.Next() {
+		c, err := scanCampaign(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		campaigns = append(campaigns, c)
+	}
+	respondJSON(w, http.StatusOK, campaigns)
+}
+
+// CreateCampaign creates a category discount campaign after checking the
+// category exists and no other campaign overlaps its range.
+func CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	var req campaignRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	start, end, msg := req.parse()
+	if msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM categories WHERE id = ?", req.CategoryID).Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists == 0 {
+		respondError(w, http.StatusNotFound, "kategori tidak ditemukan")
+		return
+	}
+	overlap, err := campaignOverlaps(req.CategoryID, start, end, 0)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if overlap {
+		respondError(w, http.StatusConflict, "sudah ada campaign lain untuk kategori ini di rentang tanggal itu")
+		return
+	}
+	res, err := db.Exec(
+		"INSERT INTO category_campaigns (name, category_id, percent, starts_at, ends_at, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		req.Name, req.CategoryID, req.Percent, start, end, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "campaign dibuat"})
+}
+
+// UpdateCampaign rewrites a campaign with the same validation as create.
+func UpdateCampaign(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req campaignRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	start, end, msg := req.parse()
+	if msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM categories WHERE id = ?", req.CategoryID).Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists == 0 {
+		respondError(w, http.StatusNotFound, "kategori tidak ditemukan")
+		return
+	}
+	overlap, err := campaignOverlaps(req.CategoryID, start, end, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if overlap {
+		respondError(w, http.StatusConflict, "sudah ada campaign lain untuk kategori ini di rentang tanggal itu")
+		return
+	}
+	res, err := db.Exec(
+		"UPDATE category_campaigns SET name = ?, category_id = ?, percent = ?, starts_at = ?, ends_at = ? WHERE id = ?",
+		req.Name, req.CategoryID, req.Percent, start, end, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "campaign tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "campaign diupdate")
+}
+
+// DeleteCampaign removes a campaign; prices revert immediately since nothing
+// was ever written to the products.
+func DeleteCampaign(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("DELETE FROM category_campaigns WHERE id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "campaign tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "campaign dihapus")
+}
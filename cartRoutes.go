@@ -0,0 +1,471 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+)
+
+func registerCartRoutes(r *Router) {
+	r.GET("/api/v1/cart", AuthMiddleware(MyCartItems))
+	r.POST("/api/v1/cart/items", AuthMiddleware(RateLimitMiddleware(CreateCartItem)))
+	r.PATCH("/api/v1/cart/items/:id", AuthMiddleware(UpdateCartItemQuantity))
+	r.DELETE("/api/v1/cart/items/:id", AuthMiddleware(DeleteCartItem))
+	r.GET("/api/v1/carts/:id", AuthMiddleware(GetCartByID))
+	r.POST("/api/v1/admin/carts/repair", RoleMiddleware("admin")(RepairCartTotals))
+}
+
+// getOrCreateCart returns the id of the user's cart, creating it on first use.
+func getOrCreateCart(userID int64) (int64, error) {
+	var cartID int64
+	err := db.QueryRow("SELECT id FROM carts WHERE user_id = ?", userID).Scan(&cartID)
+	if err == sql.ErrNoRows {
+		res, err := db.Exec("INSERT INTO carts (user_id, total_price) VALUES (?, 0)", userID)
+		if err != nil {
+			return 0, err
+		}
+		return res.LastInsertId()
+	}
+	return cartID, err
+}
+
+// AddToCartTotalPrice shifts the cart's cached total_price by delta inside
+// the caller's transaction.
+func AddToCartTotalPrice(q dbtx, cartID int64, delta float64) error {
+	_, err := q.Exec("UPDATE carts SET total_price = total_price + ? WHERE id = ?", delta, cartID)
+	return err
+}
+
+// RepairCartTotals finds carts whose cached total disagrees with the sum of
+// their items and rewrites the cache. Returns how many carts were fixed.
+func RepairCartTotals(w http.ResponseWriter, r *http.Request) {
+	res, err := db.Exec(
+		`UPDATE carts c SET c.total_price = (
+			SELECT COALESCE(SUM(ci.quantity * ci.price), 0) FROM cart_items ci WHERE ci.cart_id = c.id
+		 )
+		 WHERE c.total_price <> (
+			SELECT COALESCE(SUM(ci.quantity * ci.price), 0) FROM cart_items ci WHERE ci.cart_id = c.id
+		 )`)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	fixed, _ := res.RowsAffected()
+	respondJSON(w, http.StatusOK, map[string]any{
+		"repaired": fixed,
+		"message":  "total cart yang tidak konsisten sudah diperbaiki",
+	})
+}
+
+// cartItemView is a cart line joined with its product, variant, first image,
+// and live stock so the client needs no follow-up lookups.
+type cartItemView struct {
+	CartItem
+	ProductName  *string `json:"product_name"`
+	VariantName  *string `json:"variant_name"`
+	BundleName   *string `json:"bundle_name"`
+	ImageURL     *string `json:"image_url"`
+	CurrentStock *int    `json:"current_stock"`
+	// Options carries the customization values chosen at add-to-cart.
+	Options []ItemOption `json:"options,omitempty"`
+}
+
+// MyCartItems lists the authenticated user's cart lines with product details
+// in a single JOIN query.
+func MyCartItems(w http.ResponseWriter, r *http.Request) {
+	cartID, err := getOrCreateCart(currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rows, err := db.Query(
+		`SELECT ci.id, ci.cart_id, ci.product_id, ci.variant_id, ci.bundle_id, ci.quantity, ci.price,
+			p.name, v.name, b.name,
+			(SELECT pi.url FROM product_images pi WHERE pi.product_id = p.id AND pi.media_type = 'image' ORDER BY pi.id LIMIT 1),
+			COALESCE(v.stock, p.stock)
+		 FROM cart_items ci
+		 LEFT JOIN products p ON p.id = ci.product_id
+		 LEFT JOIN product_variants v ON v.id = ci.variant_id
+		 LEFT JOIN bundles b ON b.id = ci.bundle_id
+		 WHERE ci.cart_id = ?`,
+		cartID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	items := []cartItemView{}
+	for rows.Next() {
+		var (
+			it          cartItemView
+			productID   sql.NullInt64
+			variantID   sql.NullInt64
+			bundleID    sql.NullInt64
+			productName sql.NullString
+			variantName sql.NullString
+			bundleName  sql.NullString
+			imageURL    sql.NullString
+			stock       sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.CartID, &productID, &variantID, &bundleID, &it.Quantity, &it.Price,
+			&productName, &variantName, &bundleName, &imageURL, &stock); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if productID.Valid {
+			it.ProductID = &productID.Int64
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		if bundleID.Valid {
+			it.BundleID = &bundleID.Int64
+		}
+		if productName.Valid {
+			it.ProductName = &productName.String
+		}
+		if variantName.Valid {
+			it.VariantName = &variantName.String
+		}
+		if bundleName.Valid {
+			it.BundleName = &bundleName.String
+		}
+		if imageURL.Valid {
+			it.ImageURL = &imageURL.String
+		}
+		if stock.Valid {
+			s := int(stock.Int64)
+			it.CurrentStock = &s
+		}
+		items = append(items, it)
+	}
+	for i := range items {
+		options, err := fetchItemOptions("cart_item_options", items[i].ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		items[i].Options = options
+	}
+	var totalPrice float64
+	if err := db.QueryRow("SELECT total_price FROM carts WHERE id = ?", cartID).Scan(&totalPrice); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	in := checkoutInput{Role: currentClaims(r).Role, Total: totalPrice}
+	for _, it := range items {
+		in.ItemCount += it.Quantity
+		if it.ProductID != nil {
+			in.ProductIDs = append(in.ProductIDs, *it.ProductID)
+		}
+	}
+	violations, err := evaluateCheckoutRules(in)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var promoLines []promoLine
+	for _, it := range items {
+		if it.ProductID != nil {
+			promoLines = append(promoLines, promoLine{
+				ProductID: *it.ProductID, Quantity: it.Quantity, Price: it.Price})
+		}
+	}
+	promotions, err := evaluatePromotions(promoLines)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var promoDiscount float64
+	for _, a := range promotions {
+		promoDiscount += a.Discount
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"cart_id":         cartID,
+		"total_price":     totalPrice,
+		"items":           items,
+		"rule_violations": violations,
+		"promotions":      promotions,
+		"promo_discount":  promoDiscount,
+	})
+}
+
+type cartItemRequest struct {
+	ProductID int64          `json:"product_id"`
+	VariantID *int64         `json:"variant_id"`
+	Quantity  int            `json:"quantity"`
+	Options   []chosenOption `json:"options"`
+}
+
+// resolveItemPrice returns the current unit price, available stock, and
+// pre-order flag for a product or, when the product uses variants, for the
+// requested variant.
+func resolveItemPrice(productID int64, variantID *int64) (price float64, stock int, isPreorder bool, err error) {
+	var (
+		isVarians            bool
+		isDiscounted         bool
+		discountPrice        float64
+		discStarts, discEnds sql.NullTime
+		categoryID           int64
+	)
+	err = db.QueryRow(
+		`SELECT price, stock, is_varians, is_discounted, discount_price, discount_starts_at, discount_ends_at,
+			is_preorder, category_id FROM products WHERE id = ?`,
+		productID).Scan(&price, &stock, &isVarians, &isDiscounted, &discountPrice, &discStarts, &discEnds,
+		&isPreorder, &categoryID)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if isVarians {
+		if variantID == nil {
+			return 0, 0, false, errVariantRequired
+		}
+		err = db.QueryRow(
+			`SELECT price, stock, is_discounted, discount_price, discount_starts_at, discount_ends_at
+			 FROM product_variants
+			 WHERE id = ? AND product_id = ?`+notDeleted(""),
+			*variantID, productID).Scan(&price, &stock, &isDiscounted, &discountPrice, &discStarts, &discEnds)
+		if err != nil {
+			return 0, 0, false, err
+		}
+	}
+	if isDiscounted {
+		var startsAt, endsAt *time.Time
+		if discStarts.Valid {
+			startsAt = &discStarts.Time
+		}
+		if discEnds.Valid {
+			endsAt = &discEnds.Time
+		}
+		isDiscounted = discountWindowOpen(startsAt, endsAt)
+	}
+	listPrice := price
+	price, _ = effectivePrice(price, isDiscounted, discountPrice)
+	price, _ = campaignPrice(categoryID, listPrice, price)
+	return price, stock, isPreorder, nil
+}
+
+var errVariantRequired = errors.New("varian wajib dipilih untuk produk ini")
+
+// CreateCartItem adds a product (or variant) to the authenticated user's cart.
+func CreateCartItem(w http.ResponseWriter, r *http.Request) {
+	var req cartItemRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Quantity <= 0 {
+		respondError(w, http.StatusBadRequest, "quantity minimal 1")
+		return
+	}
+	price, stock, isPreorder, err := resolveItemPrice(req.ProductID, req.VariantID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk atau varian tidak ditemukan")
+		return
+	} else if err == errVariantRequired {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if stock < req.Quantity && !isPreorder {
+		respondError(w, http.StatusBadRequest, "stock tidak mencukupi")
+		return
+	}
+	options, surcharge, msg, err := validateChosenOptions(req.ProductID, req.Options)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	price += surcharge
+	cartID, err := getOrCreateCart(currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var id int64
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, price) VALUES (?, ?, ?, ?, ?)",
+			cartID, req.ProductID, req.VariantID, req.Quantity, price)
+		if err != nil {
+			return err
+		}
+		id, _ = res.LastInsertId()
+		if err := insertCartItemOptions(tx, id, options); err != nil {
+			return err
+		}
+		return AddToCartTotalPrice(tx, cartID, price*float64(req.Quantity))
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "item ditambahkan ke cart"})
+}
+
+// UpdateCartItemQuantity changes the quantity of a cart item.
+func UpdateCartItemQuantity(w http.ResponseWriter, r *http.Request) {
+	itemID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Quantity int `json:"quantity"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Quantity <= 0 {
+		respondError(w, http.StatusBadRequest, "quantity minimal 1")
+		return
+	}
+	cartID, err := getOrCreateCart(currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var (
+		productID sql.NullInt64
+		variantID sql.NullInt64
+		bundleID  sql.NullInt64
+		oldQty    int
+	)
+	err = db.QueryRow(
+		"SELECT product_id, variant_id, bundle_id, quantity FROM cart_items WHERE id = ? AND cart_id = ?",
+		itemID, cartID).Scan(&productID, &variantID, &bundleID, &oldQty)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "item tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var (
+		price float64
+		stock int
+	)
+	if bundleID.Valid {
+		if err := db.QueryRow("SELECT price FROM bundles WHERE id = ?", bundleID.Int64).Scan(&price); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		stock, err = bundleAvailableStock(bundleID.Int64)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		var vID *int64
+		if variantID.Valid {
+			vID = &variantID.Int64
+		}
+		var isPreorder bool
+		price, stock, isPreorder, err = resolveItemPrice(productID.Int64, vID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if isPreorder && stock < req.Quantity {
+			stock = req.Quantity
+		}
+		surcharge, err := cartItemOptionSurcharge(itemID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		price += surcharge
+	}
+	if stock < req.Quantity {
+		respondError(w, http.StatusBadRequest, "stock tidak mencukupi")
+		return
+	}
+	err = WithTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("UPDATE cart_items SET quantity = ?, price = ? WHERE id = ?",
+			req.Quantity, price, itemID); err != nil {
+			return err
+		}
+		return AddToCartTotalPrice(tx, cartID, price*float64(req.Quantity-oldQty))
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "quantity berhasil diupdate")
+}
+
+// DeleteCartItem removes an item from the authenticated user's cart.
+func DeleteCartItem(w http.ResponseWriter, r *http.Request) {
+	itemID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	cartID, err := getOrCreateCart(currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var (
+		qty   int
+		price float64
+	)
+	err = db.QueryRow("SELECT quantity, price FROM cart_items WHERE id = ? AND cart_id = ?",
+		itemID, cartID).Scan(&qty, &price)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "item tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	err = WithTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM cart_item_options WHERE cart_item_id = ?", itemID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM cart_items WHERE id = ?", itemID); err != nil {
+			return err
+		}
+		return AddToCartTotalPrice(tx, cartID, -price*float64(qty))
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "item berhasil dihapus")
+}
+
+// GetCartByID returns one cart row by id; only the owner or an admin may
+// read it.
+func GetCartByID(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var (
+		userID     int64
+		totalPrice float64
+	)
+	err = db.QueryRow("SELECT user_id, total_price FROM carts WHERE id = ?", id).Scan(&userID, &totalPrice)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "cart tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if claims := currentClaims(r); claims.Role != "admin" && claims.ID != userID {
+		respondError(w, http.StatusNotFound, "cart tidak ditemukan")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"id":          id,
+		"user_id":     userID,
+		"total_price": totalPrice,
+	})
+}
@@ -0,0 +1,104 @@
+package main
+
+import "net/http"
+
+func registerCategoryRoutes(r *Router) {
+	r.GET("/api/v1/categories", GetAllCategories)
+	r.POST("/api/v1/categories", PermissionMiddleware("categories:write")(CreateCategory))
+	r.PUT("/api/v1/categories/:id", PermissionMiddleware("categories:write")(UpdateCategory))
+	r.DELETE("/api/v1/categories/:id", PermissionMiddleware("categories:write")(DeleteCategory))
+}
+
+// GetAllCategories lists every live category; admins may pass
+// ?include_deleted=true to see soft-deleted ones too.
+func GetAllCategories(w http.ResponseWriter, r *http.Request) {
+	query := "SELECT id, name FROM categories"
+	if !includeDeleted(r) {
+		query += " WHERE deleted_at IS NULL"
+	}
+	rows, err := db.Query(query)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	categories := []Category{}
+	for rows.Next() {
+		var c Category
+		if err := rows.Scan(&c.ID, &c.Name); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		categories = append(categories, c)
+	}
+	respondJSON(w, http.StatusOK, categories)
+}
+
+// CreateCategory inserts a category.
+func CreateCategory(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Name == "" {
+		respondError(w, http.StatusBadRequest, "nama kategori wajib diisi")
+		return
+	}
+	res, err := db.Exec("INSERT INTO categories (name) VALUES (?)", req.Name)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	recordAudit(r, "create", "category", id, nil, req)
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "kategori berhasil dibuat"})
+}
+
+// UpdateCategory renames a category.
+func UpdateCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Name == "" {
+		respondError(w, http.StatusBadRequest, "nama kategori wajib diisi")
+		return
+	}
+	var oldName string
+	db.QueryRow("SELECT name FROM categories WHERE id = ?", id).Scan(&oldName)
+	res, err := db.Exec("UPDATE categories SET name = ? WHERE id = ?"+notDeleted(""), req.Name, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "kategori tidak ditemukan")
+		return
+	}
+	recordAudit(r, "update", "category", id, map[string]string{"name": oldName}, req)
+	respondMessage(w, http.StatusOK, "kategori berhasil diupdate")
+}
+
+// DeleteCategory soft-deletes a category so an accidental delete can be
+// restored.
+func DeleteCategory(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	deleted, err := softDelete("categories", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, "kategori tidak ditemukan")
+		return
+	}
+	recordAudit(r, "delete", "category", id, nil, nil)
+	respondMessage(w, http.StatusOK, "kategori berhasil dihapus")
+}
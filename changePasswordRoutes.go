@@ -0,0 +1,55 @@
+package main
+
+import "net/http"
+
+func registerChangePasswordRoutes(r *Router) {
+	r.PATCH("/api/v1/me/password", AuthMiddleware(ChangeMyPassword))
+}
+
+// ChangeMyPassword rotates the password of whichever account the JWT belongs
+// to, after re-checking the old one.
+func ChangeMyPassword(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OldPassword string `json:"old_password"`
+		NewPassword string `json:"new_password"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.OldPassword == "" {
+		respondError(w, http.StatusBadRequest, "old_password dan new_password wajib diisi")
+		return
+	}
+	if !requireValidPassword(w, req.NewPassword) {
+		return
+	}
+	claims := currentClaims(r)
+	table, ok := accountTable(claims.Role)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "role tidak dikenal")
+		return
+	}
+	var hashed string
+	if err := db.QueryRow("SELECT password FROM "+table+" WHERE id = ?", claims.ID).
+		Scan(&hashed); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !checkPassword(hashed, req.OldPassword) {
+		respondError(w, http.StatusUnauthorized, "password lama salah")
+		return
+	}
+	newHash, err := hashPassword(req.NewPassword)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, err := db.Exec("UPDATE "+table+" SET password = ? WHERE id = ?",
+		newHash, claims.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Other sessions should have to log in again with the new password.
+	if err := revokeRefreshTokens(claims.ID, claims.Role); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "password berhasil diganti")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// acquireCheckoutLock takes a per-user MySQL advisory lock so two concurrent
+// checkouts (a double-tap on "place order") cannot both turn the same cart
+// into an order. GET_LOCK is connection-scoped, so the lock is held on a
+// pinned connection and the returned release func frees both. ok is false
+// when another checkout for the same user is already in flight.
+func acquireCheckoutLock(userID int64) (release func(), ok bool, err error) {
+	name := fmt.Sprintf("checkout:%d", userID)
+	conn, err := db.Conn(context.Background())
+	if err != nil {
+		return nil, false, err
+	}
+	var got int
+	if err := conn.QueryRowContext(context.Background(),
+		"SELECT GET_LOCK(?, 0)", name).Scan(&got); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if got != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+	release = func() {
+		if _, err := conn.ExecContext(context.Background(),
+			"SELECT RELEASE_LOCK(?)", name); err != nil {
+			log.Printf("gagal melepas lock checkout %s: %v", name, err)
+		}
+		conn.Close()
+	}
+	return release, true, nil
+}
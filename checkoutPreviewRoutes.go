@@ -0,0 +1,277 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func registerCheckoutPreviewRoutes(r *Router) {
+	r.POST("/api/v1/checkout/preview", AuthMiddleware(CheckoutPreview))
+}
+
+// checkoutLine is one priced cart line with the product flags checkout needs.
+type checkoutLine struct {
+	// CartItemID links back to the cart line so its options can be copied
+	// onto the order; zero for admin-built orders.
+	CartItemID int64
+	ProductID  sql.NullInt64
+	VariantID  sql.NullInt64
+	BundleID   sql.NullInt64
+	Quantity   int
+	Price      float64
+	Preorder   bool
+	Digital    bool
+}
+
+// checkoutSummary is the fully priced result of a checkout run: the lines,
+// every discount applied, and the final total.
+type checkoutSummary struct {
+	CartID         int64
+	Lines          []checkoutLine
+	Subtotal       float64
+	Promotions     []AppliedPromotion
+	PromoDiscount  float64
+	Coupon         *Coupon
+	CouponDiscount float64
+	Total          float64
+}
+
+// computeCheckout prices the caller's cart: it loads the lines, verifies
+// stock, runs the checkout rules, applies promotions and then the coupon.
+// CreateOrder and CheckoutPreview both go through here, so the preview can
+// never disagree with the order that gets created. On failure it writes the
+// error response and returns false.
+func computeCheckout(w http.ResponseWriter, r *http.Request, couponCode string) (*checkoutSummary, bool) {
+	userID := currentUserID(r)
+	cartID, err := getOrCreateCart(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	rows, err := db.Query(
+		"SELECT id, product_id, variant_id, bundle_id, quantity, price FROM cart_items WHERE cart_id = ?", cartID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	var lines []checkoutLine
+	for rows.Next() {
+		var l checkoutLine
+		if err := rows.Scan(&l.CartItemID, &l.ProductID, &l.VariantID, &l.BundleID, &l.Quantity, &l.Price); err != nil {
+			rows.Close()
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return nil, false
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+	if len(lines) == 0 {
+		respondError(w, http.StatusBadRequest, "cart masih kosong")
+		return nil, false
+	}
+
+	var total float64
+	for i, l := range lines {
+		if l.BundleID.Valid {
+			available, err := bundleAvailableStock(l.BundleID.Int64)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return nil, false
+			}
+			if available < l.Quantity {
+				respondError(w, http.StatusBadRequest, "stock komponen bundle tidak mencukupi")
+				return nil, false
+			}
+			total += l.Price * float64(l.Quantity)
+			continue
+		}
+		var (
+			isVarians  bool
+			isPreorder bool
+			isDigital  bool
+			stock      int
+		)
+		if err := db.QueryRow("SELECT is_varians, is_preorder, is_digital, stock FROM products WHERE id = ?",
+			l.ProductID.Int64).Scan(&isVarians, &isPreorder, &isDigital, &stock); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return nil, false
+		}
+		lines[i].Preorder = isPreorder
+		lines[i].Digital = isDigital
+		if isVarians {
+			if !l.VariantID.Valid {
+				respondError(w, http.StatusBadRequest, "varian wajib dipilih untuk produk ini")
+				return nil, false
+			}
+			if err := db.QueryRow("SELECT stock FROM product_variants WHERE id = ?",
+				l.VariantID.Int64).Scan(&stock); err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return nil, false
+			}
+		}
+		if stock < l.Quantity && !isPreorder && !isDigital {
+			respondError(w, http.StatusBadRequest, "stock tidak mencukupi")
+			return nil, false
+		}
+		total += l.Price * float64(l.Quantity)
+	}
+	subtotal := total
+
+	in := checkoutInput{Role: currentClaims(r).Role, Total: total}
+	for _, l := range lines {
+		in.ItemCount += l.Quantity
+		if l.ProductID.Valid {
+			in.ProductIDs = append(in.ProductIDs, l.ProductID.Int64)
+		}
+	}
+	violations, err := evaluateCheckoutRules(in)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	if len(violations) > 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]any{"errors": violations})
+		return nil, false
+	}
+
+	var promoLines []promoLine
+	for _, l := range lines {
+		if l.ProductID.Valid {
+			promoLines = append(promoLines, promoLine{
+				ProductID: l.ProductID.Int64, Quantity: l.Quantity, Price: l.Price})
+		}
+	}
+	promotions, err := evaluatePromotions(promoLines)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	var promoDiscount float64
+	for _, a := range promotions {
+		promoDiscount += a.Discount
+	}
+	total -= promoDiscount
+
+	var coupon *Coupon
+	var discount float64
+	if couponCode != "" {
+		var msg string
+		coupon, msg = findValidCoupon(couponCode, userID)
+		if coupon == nil {
+			respondError(w, http.StatusBadRequest, msg)
+			return nil, false
+		}
+		if coupon.MinOrderTotal > 0 && total < coupon.MinOrderTotal {
+			respondError(w, http.StatusBadRequest, "total order belum memenuhi minimum kupon")
+			return nil, false
+		}
+		base := total
+		if coupon.ProductID != nil || coupon.CategoryID != nil {
+			base = 0
+			for _, l := range lines {
+				if !l.ProductID.Valid {
+					continue
+				}
+				if coupon.ProductID != nil {
+					if l.ProductID.Int64 == *coupon.ProductID {
+						base += l.Price * float64(l.Quantity)
+					}
+					continue
+				}
+				var categoryID int64
+				if err := db.QueryRow("SELECT category_id FROM products WHERE id = ?",
+					l.ProductID.Int64).Scan(&categoryID); err != nil {
+					respondError(w, http.StatusInternalServerError, err.Error())
+					return nil, false
+				}
+				if categoryID == *coupon.CategoryID {
+					base += l.Price * float64(l.Quantity)
+				}
+			}
+			if base == 0 {
+				respondError(w, http.StatusBadRequest, "tidak ada item di cart yang cocok dengan kupon")
+				return nil, false
+			}
+		}
+		discount = couponDiscount(coupon, base)
+		total -= discount
+	}
+
+	return &checkoutSummary{
+		CartID:         cartID,
+		Lines:          lines,
+		Subtotal:       subtotal,
+		Promotions:     promotions,
+		PromoDiscount:  promoDiscount,
+		Coupon:         coupon,
+		CouponDiscount: discount,
+		Total:          total,
+	}, true
+}
+
+// CheckoutPreview runs the full checkout pricing without creating anything,
+// so the confirmation screen shows exactly what the order will cost.
+func CheckoutPreview(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		CouponCode    string `json:"coupon_code"`
+		PaymentMethod string `json:"payment_method"`
+		DeliveryArea  string `json:"delivery_area"`
+	}
+	decodeBody(r, &req)
+	if req.PaymentMethod == "" {
+		req.PaymentMethod = "transfer"
+	}
+	if req.PaymentMethod != "transfer" && req.PaymentMethod != "cod" {
+		respondError(w, http.StatusBadRequest, "payment_method harus transfer atau cod")
+		return
+	}
+	cs, ok := computeCheckout(w, r, req.CouponCode)
+	if !ok {
+		return
+	}
+	if req.PaymentMethod == "cod" {
+		if msg := codEligibilityError(cs.Total, req.DeliveryArea); msg != "" {
+			respondError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+	type previewItem struct {
+		ProductID *int64  `json:"product_id"`
+		VariantID *int64  `json:"variant_id"`
+		BundleID  *int64  `json:"bundle_id"`
+		Quantity  int     `json:"quantity"`
+		Price     float64 `json:"price"`
+		LineTotal float64 `json:"line_total"`
+	}
+	items := []previewItem{}
+	for _, l := range cs.Lines {
+		it := previewItem{
+			Quantity:  l.Quantity,
+			Price:     l.Price,
+			LineTotal: l.Price * float64(l.Quantity),
+		}
+		if l.ProductID.Valid {
+			it.ProductID = &l.ProductID.Int64
+		}
+		if l.VariantID.Valid {
+			it.VariantID = &l.VariantID.Int64
+		}
+		if l.BundleID.Valid {
+			it.BundleID = &l.BundleID.Int64
+		}
+		items = append(items, it)
+	}
+	resp := map[string]any{
+		"items":          items,
+		"subtotal":       cs.Subtotal,
+		"promotions":     cs.Promotions,
+		"promo_discount": cs.PromoDiscount,
+		"discount":       cs.CouponDiscount,
+		"total_price":    cs.Total,
+		"payment_method": req.PaymentMethod,
+	}
+	if cs.Coupon != nil {
+		resp["coupon_code"] = cs.Coupon.Code
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func registerCheckoutRuleRoutes(r *Router) {
+	r.GET("/api/v1/admin/checkout-rules", RoleMiddleware("admin")(GetCheckoutRules))
+	r.POST("/api/v1/admin/checkout-rules", RoleMiddleware("admin")(CreateCheckoutRule))
+	r.PUT("/api/v1/admin/checkout-rules/:id", RoleMiddleware("admin")(UpdateCheckoutRule))
+	r.DELETE("/api/v1/admin/checkout-rules/:id", RoleMiddleware("admin")(DeleteCheckoutRule))
+}
+
+// CheckoutRule is one configurable restriction evaluated at checkout.
+// Supported types: min_order_total (value = amount), max_items (value =
+// count), category_blocked (value = category id), allowed_roles (value =
+// comma-separated roles).
+type CheckoutRule struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	RuleType  string    `json:"rule_type"`
+	Value     string    `json:"value"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+var checkoutRuleTypes = map[string]bool{
+	"min_order_total":  true,
+	"max_items":        true,
+	"category_blocked": true,
+	"allowed_roles":    true,
+}
+
+// checkoutInput is everything the rules engine needs to judge one checkout.
+type checkoutInput struct {
+	Role       string
+	Total      float64
+	ItemCount  int
+	ProductIDs []int64
+}
+
+// evaluateCheckoutRules runs every active rule against the checkout and
+// returns the violation messages, empty when the checkout passes.
+func evaluateCheckoutRules(in checkoutInput) ([]string, error) {
+	rows, err := db.Query(
+		"SELECT id, name, rule_type, value, is_active, created_at FROM checkout_rules WHERE is_active = 1")
+	if err != nil {
+		return nil, err
+	}
+	var rules []CheckoutRule
+	for rows.Next() {
+		var cr CheckoutRule
+		if err := rows.Scan(&cr.ID, &cr.Name, &cr.RuleType, &cr.Value, &cr.IsActive, &cr.CreatedAt); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rules = append(rules, cr)
+	}
+	rows.Close()
+
+	var categoryIDs map[int64]bool
+	violations := []string{}
+	for _, cr := range rules {
+		switch cr.RuleType {
+		case "min_order_total":
+			min, err := strconv.ParseFloat(cr.Value, 64)
+			if err == nil && in.Total < min {
+				violations = append(violations, fmt.Sprintf("total order minimal %.0f", min))
+			}
+		case "max_items":
+			max, err := strconv.Atoi(cr.Value)
+			if err == nil && in.ItemCount > max {
+				violations = append(violations, fmt.Sprintf("maksimal %d item per order", max))
+			}
+		case "category_blocked":
+			blocked, err := strconv.ParseInt(cr.Value, 10, 64)
+			if err != nil {
+				continue
+			}
+			if categoryIDs == nil {
+				categoryIDs = map[int64]bool{}
+				for _, pid := range in.ProductIDs {
+					var categoryID int64
+					if err := db.QueryRow("SELECT category_id FROM products WHERE id = ?",
+						pid).Scan(&categoryID); err != nil {
+						return nil, err
+					}
+					categoryIDs[categoryID] = true
+				}
+			}
+			if categoryIDs[blocked] {
+				violations = append(violations, "kategori produk di cart sedang tidak bisa dicheckout")
+			}
+		case "allowed_roles":
+			allowed := false
+			for _, role := range strings.Split(cr.Value, ",") {
+				if strings.TrimSpace(role) == in.Role {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				violations = append(violations, "akun kamu tidak diizinkan melakukan checkout ini")
+			}
+		}
+	}
+	return violations, nil
+}
+
+// GetCheckoutRules lists every checkout rule.
+func GetCheckoutRules(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT id, name, rule_type, value, is_active, created_at FROM checkout_rules")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	rules := []CheckoutRule{}
+	for rows.Next() {
+		var cr CheckoutRule
+		if err := rows.Scan(&cr.ID, &cr.Name, &cr.RuleType, &cr.Value, &cr.IsActive, &cr.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		rules = append(rules, cr)
+	}
+	respondJSON(w, http.StatusOK, rules)
+}
+
+type checkoutRuleRequest struct {
+	Name     string `json:"name"`
+	RuleType string `json:"rule_type"`
+	Value    string `json:"value"`
+	IsActive bool   `json:"is_active"`
+}
+
+func (req *checkoutRuleRequest) validate() string {
+	if req.Name == "" {
+		return "nama rule wajib diisi"
+	}
+	if !checkoutRuleTypes[req.RuleType] {
+		return "rule_type tidak dikenal"
+	}
+	if req.Value == "" {
+		return "value wajib diisi"
+	}
+	return ""
+}
+
+// CreateCheckoutRule inserts a checkout rule.
+func CreateCheckoutRule(w http.ResponseWriter, r *http.Request) {
+	var req checkoutRuleRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	res, err := db.Exec(
+		"INSERT INTO checkout_rules (name, rule_type, value, is_active, created_at) VALUES (?, ?, ?, ?, ?)",
+		req.Name, req.RuleType, req.Value, req.IsActive, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "rule berhasil dibuat"})
+}
+
+// UpdateCheckoutRule overwrites an existing checkout rule.
+func UpdateCheckoutRule(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req checkoutRuleRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	res, err := db.Exec(
+		"UPDATE checkout_rules SET name = ?, rule_type = ?, value = ?, is_active = ? WHERE id = ?",
+		req.Name, req.RuleType, req.Value, req.IsActive, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "rule tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "rule berhasil diupdate")
+}
+
+// DeleteCheckoutRule removes a checkout rule.
+func DeleteCheckoutRule(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("DELETE FROM checkout_rules WHERE id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "rule tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "rule berhasil dihapus")
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+func registerCodRoutes(r *Router) {
+	r.POST("/api/v1/cod/settlements", RoleMiddleware("cashier", "driver", "admin")(RecordCodSettlement))
+	r.GET("/api/v1/admin/cod/report", RoleMiddleware("admin")(GetCodReport))
+}
+
+// codEligibilityError checks whether an order may use cash on delivery:
+// the total must stay under the configured cap, and when a serviceable-area
+// list is configured the delivery area has to be on it. Empty string means
+// eligible.
+func codEligibilityError(total float64, area string) string {
+	if total > config.CODMaxTotal {
+		return fmt.Sprintf("COD hanya tersedia untuk order sampai %.0f", config.CODMaxTotal)
+	}
+	if config.CODAreas == "" {
+		return ""
+	}
+	if area == "" {
+		return "delivery_area wajib diisi untuk COD"
+	}
+	for _, serviced := range strings.Split(config.CODAreas, ",") {
+		if strings.EqualFold(strings.TrimSpace(serviced), strings.TrimSpace(area)) {
+			return ""
+		}
+	}
+	return "area pengiriman belum terjangkau COD"
+}
+
+// RecordCodSettlement records cash collected for a COD order and finalizes it
+// the same way an online payment confirmation would.
+func RecordCodSettlement(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		OrderID int64   `json:"order_id"`
+		Amount  float64 `json:"amount"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.OrderID == 0 {
+		respondError(w, http.StatusBadRequest, "order_id wajib diisi")
+		return
+	}
+	var (
+		total  float64
+		status string
+		method string
+	)
+	err := db.QueryRow("SELECT total_price, status, payment_method FROM orders WHERE id = ?",
+		req.OrderID).Scan(&total, &status, &method)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if method != "cod" {
+		respondError(w, http.StatusBadRequest, "order ini bukan COD")
+		return
+	}
+	if status != "waitToBuy" {
+		respondError(w, http.StatusBadRequest, "order tidak menunggu pembayaran")
+		return
+	}
+	if req.Amount < total {
+		respondError(w, http.StatusBadRequest, "jumlah yang ditagih kurang dari total order")
+		return
+	}
+	if _, err := db.Exec(
+		"INSERT INTO cod_settlements (order_id, collected_by, amount, created_at) VALUES (?, ?, ?, ?)",
+		req.OrderID, currentUserID(r), req.Amount, nowUTC()); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := finalizeOrderPayment(req.OrderID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "setoran COD berhasil dicatat")
+}
+
+// GetCodReport reconciles COD: cash recorded per collector plus COD orders
+// still waiting for settlement.
+func GetCodReport(w http.ResponseWriter, r *http.Request) {
+	rows, err := readOnlyDB().Query(
+		`SELECT s.collected_by, e.username, COUNT(*), SUM(s.amount)
+		 FROM cod_settlements s
+		 LEFT JOIN employees e ON e.id = s.collected_by
+		 GROUP BY s.collected_by, e.username`)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	type collectorRow struct {
+		CollectorID int64   `json:"collector_id"`
+		Username    string  `json:"username"`
+		Count       int     `json:"count"`
+		Total       float64 `json:"total"`
+	}
+	collectors := []collectorRow{}
+	for rows.Next() {
+		var (
+			c        collectorRow
+			username sql.NullString
+		)
+		if err := rows.Scan(&c.CollectorID, &username, &c.Count, &c.Total); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.Username = username.String
+		collectors = append(collectors, c)
+	}
+	var (
+		outstandingCount int
+		outstandingTotal sql.NullFloat64
+	)
+	if err := db.QueryRow(
+		"SELECT COUNT(*), SUM(total_price) FROM orders WHERE payment_method = 'cod' AND status = 'waitToBuy'").
+		Scan(&outstandingCount, &outstandingTotal); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"collected":         collectors,
+		"outstanding_count": outstandingCount,
+		"outstanding_total": outstandingTotal.Float64,
+	})
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"os"
+	"strconv"
+)
+
+// Config holds runtime configuration loaded from environment variables.
+type Config struct {
+	Port   string
+	DBUser string
+	DBPass string
+	DBHost string
+	DBPort string
+	DBName string
+
+	// DBReplicaHost enables read-replica routing when set; the replica shares
+	// the primary's credentials and database name.
+	DBReplicaHost string
+	DBReplicaPort string
+
+	JWTSecret string
+	// JWTKeys optionally lists extra signing keys as "kid=secret,...",
+	// newest first; JWT_SECRET stays in the set as kid "v1".
+	JWTKeys   string
+	DisplayTZ string
+	// CookieAuth additionally delivers the JWT as an HttpOnly cookie with a
+	// double-submit CSRF token, for browser frontends.
+	CookieAuth bool
+
+	// Token lifetimes; remember_me at login picks the longer refresh window.
+	AccessTokenTTLMinutes int
+	RefreshTokenDays      int
+	RememberMeDays        int
+
+	// BaseURL is the public origin of this API, used when building links that
+	// end up in emails.
+	BaseURL string
+
+	SearchEngineURL string
+	SearchAPIKey    string
+
+	PasswordMinLen int
+	PwnedRangeURL  string
+	// PasswordDenylist appends comma-separated entries to the built-in list
+	// of banned common passwords.
+	PasswordDenylist string
+
+	// HeartRegenDays is how many loss-free days earn one heart back;
+	// 0 disables automatic regeneration.
+	HeartRegenDays int
+
+	// Loyalty points: one point per PointsEarnPer of order total (0 disables
+	// earning); each point is worth PointValue as a checkout discount.
+	PointsEarnPer float64
+	PointValue    float64
+	// ReferralRewardPoints is paid to both sides of a referral after the
+	// referee's first paid order; 0 disables the program.
+	ReferralRewardPoints int
+
+	// Token-bucket budget for mutating endpoints, per account or source IP.
+	RateLimitPerMinute int
+	RateLimitBurst     int
+	// RedisAddr enables the shared login throttle across replicas when set.
+	RedisAddr string
+
+	CaptchaSecret    string
+	CaptchaVerifyURL string
+
+	MailProvider   string
+	MailFrom       string
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUser       string
+	SMTPPass       string
+	SendGridAPIKey string
+
+	SMSProvider string
+	SMSFrom     string
+	TwilioSID   string
+	TwilioToken string
+
+	WAProvider string
+	WAToken    string
+	WAPhoneID  string
+
+	CODMaxTotal float64
+	CODAreas    string
+
+	QRISProvider      string
+	QRISAPIURL        string
+	QRISAPIKey        string
+	QRISWebhookSecret string
+
+	// Google OAuth login is disabled while GoogleClientID is empty.
+	GoogleClientID     string
+	GoogleClientSecret string
+	GoogleRedirectURL  string
+	GoogleTokenURL     string
+	GoogleUserinfoURL  string
+}
+
+var config Config
+
+// LoadConfig reads configuration from the environment with development defaults.
+func LoadConfig() {
+	config = Config{
+		Port:   envOr("PORT", "8080"),
+		DBUser: envOr("DB_USER", "root"),
+		DBPass: envOr("DB_PASS", ""),
+		DBHost: envOr("DB_HOST", "127.0.0.1"),
+		DBPort: envOr("DB_PORT", "3306"),
+		DBName: envOr("DB_NAME", "uts_ppt"),
+
+		DBReplicaHost: envOr("DB_REPLICA_HOST", ""),
+		DBReplicaPort: envOr("DB_REPLICA_PORT", "3306"),
+
+		JWTSecret:  envOr("JWT_SECRET", "rahasia-uts-ppt"),
+		JWTKeys:    envOr("JWT_KEYS", ""),
+		CookieAuth: envOr("COOKIE_AUTH", "") == "true",
+
+		AccessTokenTTLMinutes: envIntOr("ACCESS_TOKEN_TTL_MINUTES", 24*60),
+		RefreshTokenDays:      envIntOr("REFRESH_TOKEN_DAYS", 30),
+		RememberMeDays:        envIntOr("REMEMBER_ME_DAYS", 90),
+		BaseURL:               envOr("BASE_URL", "http://localhost:8080"),
+		DisplayTZ:             envOr("DISPLAY_TZ", "UTC"),
+
+		SearchEngineURL: envOr("SEARCH_ENGINE_URL", ""),
+		SearchAPIKey:    envOr("SEARCH_API_KEY", ""),
+
+		PasswordMinLen:   envIntOr("PASSWORD_MIN_LEN", 6),
+		PwnedRangeURL:    envOr("PWNED_RANGE_URL", ""),
+		PasswordDenylist: envOr("PASSWORD_DENYLIST", ""),
+
+		HeartRegenDays: envIntOr("HEART_REGEN_DAYS", 7),
+
+		PointsEarnPer:        envFloatOr("POINTS_EARN_PER", 10000),
+		PointValue:           envFloatOr("POINT_VALUE", 100),
+		ReferralRewardPoints: envIntOr("REFERRAL_REWARD_POINTS", 100),
+
+		RateLimitPerMinute: envIntOr("RATE_LIMIT_PER_MINUTE", 60),
+		RateLimitBurst:     envIntOr("RATE_LIMIT_BURST", 20),
+		RedisAddr:          envOr("REDIS_ADDR", ""),
+
+		// Empty secret disables captcha checks entirely.
+		CaptchaSecret:    envOr("CAPTCHA_SECRET", ""),
+		CaptchaVerifyURL: envOr("CAPTCHA_VERIFY_URL", "https://www.google.com/recaptcha/api/siteverify"),
+
+		// "log" keeps dev environments from emailing real customers.
+		MailProvider:   envOr("MAIL_PROVIDER", "log"),
+		MailFrom:       envOr("MAIL_FROM", "noreply@uts-ppt.local"),
+		SMTPHost:       envOr("SMTP_HOST", "127.0.0.1"),
+		SMTPPort:       envOr("SMTP_PORT", "25"),
+		SMTPUser:       envOr("SMTP_USER", ""),
+		SMTPPass:       envOr("SMTP_PASS", ""),
+		SendGridAPIKey: envOr("SENDGRID_API_KEY", ""),
+
+		SMSProvider: envOr("SMS_PROVIDER", "log"),
+		SMSFrom:     envOr("SMS_FROM", ""),
+		TwilioSID:   envOr("TWILIO_SID", ""),
+		TwilioToken: envOr("TWILIO_TOKEN", ""),
+
+		WAProvider: envOr("WA_PROVIDER", "log"),
+		WAToken:    envOr("WA_TOKEN", ""),
+		WAPhoneID:  envOr("WA_PHONE_ID", ""),
+
+		// Empty COD_AREAS means every area is serviceable.
+		CODMaxTotal: envFloatOr("COD_MAX_TOTAL", 1000000),
+		CODAreas:    envOr("COD_AREAS", ""),
+
+		QRISProvider:      envOr("QRIS_PROVIDER", "log"),
+		QRISAPIURL:        envOr("QRIS_API_URL", ""),
+		QRISAPIKey:        envOr("QRIS_API_KEY", ""),
+		QRISWebhookSecret: envOr("QRIS_WEBHOOK_SECRET", "rahasia-qris"),
+
+		GoogleClientID:     envOr("GOOGLE_CLIENT_ID", ""),
+		GoogleClientSecret: envOr("GOOGLE_CLIENT_SECRET", ""),
+		GoogleRedirectURL:  envOr("GOOGLE_REDIRECT_URL", ""),
+		GoogleTokenURL:     envOr("GOOGLE_TOKEN_URL", "https://oauth2.googleapis.com/token"),
+		GoogleUserinfoURL:  envOr("GOOGLE_USERINFO_URL", "https://openidconnect.googleapis.com/v1/userinfo"),
+	}
+	loadDisplayLocation()
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envIntOr(key string, fallback int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+func envFloatOr(key string, fallback float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return fallback
+}
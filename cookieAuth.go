@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Cookie auth mode: when COOKIE_AUTH is enabled the login response also sets
+// the JWT as a Secure HttpOnly cookie plus a readable CSRF cookie, so browser
+// frontends never touch localStorage. Mutating requests authenticated via the
+// cookie must echo the CSRF cookie in an X-CSRF-Token header (double submit).
+
+const (
+	authCookieName = "auth_token"
+	csrfCookieName = "csrf_token"
+)
+
+var errCSRFMismatch = errors.New("csrf token tidak cocok")
+
+// setAuthCookies attaches the token and a fresh CSRF token to the response.
+func setAuthCookies(w http.ResponseWriter, token string) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return
+	}
+	expires := nowUTC().Add(time.Duration(config.AccessTokenTTLMinutes) * time.Minute)
+	http.SetCookie(w, &http.Cookie{
+		Name:     authCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    hex.EncodeToString(raw),
+		Path:     "/",
+		Expires:  expires,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// clearAuthCookies expires both cookies, for logout.
+func clearAuthCookies(w http.ResponseWriter) {
+	for _, name := range []string{authCookieName, csrfCookieName} {
+		http.SetCookie(w, &http.Cookie{Name: name, Value: "", Path: "/", MaxAge: -1})
+	}
+}
+
+// tokenFromCookie returns the JWT from the auth cookie. On mutating methods
+// it enforces the double-submit check and reports errCSRFMismatch when the
+// header and cookie disagree.
+func tokenFromCookie(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(authCookieName)
+	if err != nil || cookie.Value == "" {
+		return "", http.ErrNoCookie
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return cookie.Value, nil
+	}
+	csrf, err := r.Cookie(csrfCookieName)
+	if err != nil || csrf.Value == "" ||
+		!hmac.Equal([]byte(csrf.Value), []byte(r.Header.Get("X-CSRF-Token"))) {
+		return "", errCSRFMismatch
+	}
+	return cookie.Value, nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func registerCouponRoutes(r *Router) {
+	r.GET("/api/v1/admin/coupons", RoleMiddleware("admin")(GetAllCoupons))
+	r.POST("/api/v1/admin/coupons", RoleMiddleware("admin")(CreateCoupon))
+	r.PUT("/api/v1/admin/coupons/:id", RoleMiddleware("admin")(UpdateCoupon))
+	r.DELETE("/api/v1/admin/coupons/:id", RoleMiddleware("admin")(DeleteCoupon))
+	r.GET("/api/v1/coupons/validate", ValidateCoupon)
+}
+
+// Coupon is a discount code redeemable at checkout.
+type Coupon struct {
+	ID            int64      `json:"id"`
+	Code          string     `json:"code"`
+	Type          string     `json:"type"`
+	Value         float64    `json:"value"`
+	MinOrderTotal float64    `json:"min_order_total"`
+	PerUserLimit  int        `json:"per_user_limit"`
+	UsageLimit    int        `json:"usage_limit"`
+	UsedCount     int        `json:"used_count"`
+	ProductID     *int64     `json:"product_id"`
+	CategoryID    *int64     `json:"category_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	IsActive      bool       `json:"is_active"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+const couponColumns = "id, code, type, value, min_order_total, per_user_limit, usage_limit, used_count, product_id, category_id, starts_at, expires_at, is_active, created_at"
+
+func scanCoupon(row interface{ Scan(...any) error }) (Coupon, error) {
+	var (
+		c          Coupon
+		productID  sql.NullInt64
+		categoryID sql.NullInt64
+		startsAt   sql.NullTime
+		expiresAt  sql.NullTime
+	)
+	err := row.Scan(&c.ID, &c.Code, &c.Type, &c.Value, &c.MinOrderTotal, &c.PerUserLimit,
+		&c.UsageLimit, &c.UsedCount, &productID, &categoryID, &startsAt, &expiresAt,
+		&c.IsActive, &c.CreatedAt)
+	if productID.Valid {
+		c.ProductID = &productID.Int64
+	}
+	if categoryID.Valid {
+		c.CategoryID = &categoryID.Int64
+	}
+	if startsAt.Valid {
+		c.StartsAt = &startsAt.Time
+	}
+	if expiresAt.Valid {
+		c.ExpiresAt = &expiresAt.Time
+	}
+	return c, err
+}
+
+// GetAllCoupons lists every live coupon for admins; ?include_deleted=true
+// shows soft-deleted ones too.
+func GetAllCoupons(w http.ResponseWriter, r *http.Request) {
+	query := "SELECT " + couponColumns + " FROM coupons"
+	if !includeDeleted(r) {
+		query += " WHERE deleted_at IS NULL"
+	}
+	query += " ORDER BY created_at DESC"
+	rows, err := db.Query(query)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	coupons := []Coupon{}
+	for rows.Next() {
+		c, err := scanCoupon(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		coupons = append(coupons, c)
+	}
+	respondJSON(w, http.StatusOK, coupons)
+}
+
+type couponRequest struct {
+	Code          string     `json:"code"`
+	Type          string     `json:"type"`
+	Value         float64    `json:"value"`
+	MinOrderTotal float64    `json:"min_order_total"`
+	PerUserLimit  int        `json:"per_user_limit"`
+	UsageLimit    int        `json:"usage_limit"`
+	ProductID     *int64     `json:"product_id"`
+	CategoryID    *int64     `json:"category_id"`
+	StartsAt      *time.Time `json:"starts_at"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	IsActive      bool       `json:"is_active"`
+}
+
+func (req *couponRequest) validate() string {
+	req.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	if req.Code == "" {
+		return "kode kupon wajib diisi"
+	}
+	if req.Type != "percentage" && req.Type != "fixed" {
+		return "type kupon harus percentage atau fixed"
+	}
+	if req.Value <= 0 {
+		return "nilai kupon harus lebih dari nol"
+	}
+	if req.Type == "percentage" && req.Value > 100 {
+		return "persentase kupon maksimal 100"
+	}
+	if req.ProductID != nil && req.CategoryID != nil {
+		return "kupon hanya boleh discope ke produk atau kategori, tidak keduanya"
+	}
+	return ""
+}
+
+// CreateCoupon inserts a coupon.
+func CreateCoupon(w http.ResponseWriter, r *http.Request) {
+	var req couponRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM coupons WHERE code = ?", req.Code).Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists > 0 {
+		respondError(w, http.StatusConflict, "kode kupon sudah dipakai")
+		return
+	}
+	res, err := db.Exec(
+		`INSERT INTO coupons (code, type, value, min_order_total, per_user_limit, usage_limit,
+			used_count, product_id, category_id, starts_at, expires_at, is_active, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, 0, ?, ?, ?, ?, ?, ?)`,
+		req.Code, req.Type, req.Value, req.MinOrderTotal, req.PerUserLimit, req.UsageLimit,
+		req.ProductID, req.CategoryID, req.StartsAt, req.ExpiresAt, req.IsActive, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "kupon berhasil dibuat"})
+}
+
+// UpdateCoupon overwrites an existing coupon.
+func UpdateCoupon(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req couponRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	res, err := db.Exec(
+		`UPDATE coupons SET code = ?, type = ?, value = ?, min_order_total = ?, per_user_limit = ?,
+			usage_limit = ?, product_id = ?, category_id = ?, starts_at = ?, expires_at = ?, is_active = ?
+		 WHERE id = ?`,
+		req.Code, req.Type, req.Value, req.MinOrderTotal, req.PerUserLimit, req.UsageLimit,
+		req.ProductID, req.CategoryID, req.StartsAt, req.ExpiresAt, req.IsActive, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "kupon tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "kupon berhasil diupdate")
+}
+
+// DeleteCoupon removes a coupon.
+func DeleteCoupon(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	deleted, err := softDelete("coupons", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, "kupon tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "kupon berhasil dihapus")
+}
+
+// findValidCoupon loads a coupon by code and checks every rule that does not
+// depend on the cart contents. userID 0 skips the per-user limit check.
+func findValidCoupon(code string, userID int64) (*Coupon, string) {
+	c, err := scanCoupon(db.QueryRow(
+		"SELECT "+couponColumns+" FROM coupons WHERE code = ?"+notDeleted(""),
+		strings.ToUpper(strings.TrimSpace(code))))
+	if err == sql.ErrNoRows {
+		return nil, "kupon tidak ditemukan"
+	} else if err != nil {
+		return nil, err.Error()
+	}
+	if !c.IsActive {
+		return nil, "kupon tidak aktif"
+	}
+	now := nowUTC()
+	if c.StartsAt != nil && now.Before(*c.StartsAt) {
+		return nil, "kupon belum berlaku"
+	}
+	if c.ExpiresAt != nil && now.After(*c.ExpiresAt) {
+		return nil, "kupon sudah kadaluarsa"
+	}
+	if c.UsageLimit > 0 && c.UsedCount >= c.UsageLimit {
+		return nil, "kuota kupon sudah habis"
+	}
+	if userID != 0 && c.PerUserLimit > 0 {
+		var used int
+		if err := db.QueryRow("SELECT COUNT(*) FROM coupon_usages WHERE coupon_id = ? AND user_id = ?",
+			c.ID, userID).Scan(&used); err != nil {
+			return nil, err.Error()
+		}
+		if used >= c.PerUserLimit {
+			return nil, "kamu sudah mencapai batas pemakaian kupon ini"
+		}
+	}
+	return &c, ""
+}
+
+// couponDiscount computes the discount amount over the eligible base price.
+func couponDiscount(c *Coupon, base float64) float64 {
+	if base <= 0 {
+		return 0
+	}
+	if c.Type == "percentage" {
+		return base * c.Value / 100
+	}
+	if c.Value > base {
+		return base
+	}
+	return c.Value
+}
+
+// recordCouponUsage tracks a redemption against the coupon and its user
+// quota, using the caller's transaction.
+func recordCouponUsage(q dbtx, couponID, userID, orderID int64) error {
+	if _, err := q.Exec(
+		"INSERT INTO coupon_usages (coupon_id, user_id, order_id, used_at) VALUES (?, ?, ?, ?)",
+		couponID, userID, orderID, nowUTC()); err != nil {
+		return err
+	}
+	_, err := q.Exec("UPDATE coupons SET used_count = used_count + 1 WHERE id = ?", couponID)
+	return err
+}
+
+// ValidateCoupon lets the storefront pre-check a code before checkout. When a
+// bearer token is supplied the per-user limit is checked too.
+func ValidateCoupon(w http.ResponseWriter, r *http.Request) {
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		respondError(w, http.StatusBadRequest, "parameter code wajib diisi")
+		return
+	}
+	var userID int64
+	if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		if claims, err := parseToken(strings.TrimPrefix(header, "Bearer ")); err == nil {
+			userID = claims.ID
+		}
+	}
+	c, msg := findValidCoupon(code, userID)
+	if c == nil {
+		respondJSON(w, http.StatusOK, map[string]any{"valid": false, "reason": msg})
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"valid": true, "coupon": c})
+}
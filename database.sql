@@ -0,0 +1,782 @@
+-- Skema database UTS-PPT (MySQL)
+
+CREATE TABLE users (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(100) NOT NULL UNIQUE,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    phone VARCHAR(20) NULL UNIQUE,
+    wa_opt_in TINYINT(1) NOT NULL DEFAULT 0,
+    password VARCHAR(255) NOT NULL,
+    hearts INT NOT NULL DEFAULT 3,
+    points INT NOT NULL DEFAULT 0,
+    is_verified TINYINT(1) NOT NULL DEFAULT 0,
+    google_id VARCHAR(64) NULL UNIQUE,
+    referral_code VARCHAR(12) NULL UNIQUE,
+    is_banned TINYINT(1) NOT NULL DEFAULT 0,
+    banned_at DATETIME NULL,
+    deleted_at DATETIME NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE admins (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL
+);
+
+CREATE TABLE employees (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    username VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL UNIQUE,
+    password VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL
+);
+
+CREATE TABLE categories (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    deleted_at DATETIME NULL
+);
+
+CREATE TABLE products (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    category_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    barcode VARCHAR(64) NULL UNIQUE,
+    sku VARCHAR(64) NULL UNIQUE,
+    price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    stock INT NOT NULL DEFAULT 0,
+    low_stock_threshold INT NOT NULL DEFAULT 0,
+    is_varians TINYINT(1) NOT NULL DEFAULT 0,
+    is_discounted TINYINT(1) NOT NULL DEFAULT 0,
+    discount_price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    discount_starts_at DATETIME NULL,
+    discount_ends_at DATETIME NULL,
+    is_service TINYINT(1) NOT NULL DEFAULT 0,
+    is_digital TINYINT(1) NOT NULL DEFAULT 0,
+    is_preorder TINYINT(1) NOT NULL DEFAULT 0,
+    expected_available_at DATETIME NULL,
+    weight_grams INT NULL,
+    length_mm INT NULL,
+    width_mm INT NULL,
+    height_mm INT NULL,
+    unit VARCHAR(20) NOT NULL DEFAULT 'pcs',
+    status VARCHAR(20) NOT NULL DEFAULT 'published',
+    deleted_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE product_variants (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    sku VARCHAR(64) NULL UNIQUE,
+    barcode VARCHAR(64) NULL UNIQUE,
+    price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    stock INT NOT NULL DEFAULT 0,
+    low_stock_threshold INT NOT NULL DEFAULT 0,
+    is_discounted TINYINT(1) NOT NULL DEFAULT 0,
+    discount_price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    discount_starts_at DATETIME NULL,
+    discount_ends_at DATETIME NULL,
+    weight_grams INT NULL,
+    version INT NOT NULL DEFAULT 0,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE product_images (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    media_type VARCHAR(10) NOT NULL DEFAULT 'image',
+    thumbnail_url VARCHAR(500) NULL,
+    duration_seconds INT NULL,
+    deleted_at DATETIME NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE product_files (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    file_url VARCHAR(500) NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE license_keys (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    license_key VARCHAR(255) NOT NULL,
+    order_id BIGINT NULL,
+    assigned_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE bundles (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    price DECIMAL(12,2) NOT NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE bundle_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    bundle_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    quantity INT NOT NULL,
+    FOREIGN KEY (bundle_id) REFERENCES bundles(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE carts (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL UNIQUE,
+    total_price DECIMAL(12,2) NOT NULL DEFAULT 0,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE cart_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    cart_id BIGINT NOT NULL,
+    product_id BIGINT NULL,
+    variant_id BIGINT NULL,
+    bundle_id BIGINT NULL,
+    quantity INT NOT NULL,
+    price DECIMAL(12,2) NOT NULL,
+    FOREIGN KEY (cart_id) REFERENCES carts(id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (bundle_id) REFERENCES bundles(id)
+);
+
+CREATE TABLE orders (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    total_price DECIMAL(12,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'waitToBuy',
+    payment_method VARCHAR(20) NOT NULL DEFAULT 'transfer',
+    address_id BIGINT NULL,
+    timer_expiration DATETIME NULL,
+    expired_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE order_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    fulfillment_id BIGINT NULL,
+    product_id BIGINT NULL,
+    variant_id BIGINT NULL,
+    bundle_id BIGINT NULL,
+    quantity INT NOT NULL,
+    price DECIMAL(12,2) NOT NULL,
+    is_preorder TINYINT(1) NOT NULL DEFAULT 0,
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (bundle_id) REFERENCES bundles(id)
+);
+
+CREATE TABLE stock_reservations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    order_id BIGINT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    quantity INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    status_changed_at DATETIME NULL,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE returns (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    photos TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'requested',
+    resolution VARCHAR(20) NULL,
+    restocked TINYINT(1) NOT NULL DEFAULT 0,
+    admin_note TEXT NULL,
+    created_at DATETIME NOT NULL,
+    resolved_at DATETIME NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE notifications (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    is_read TINYINT(1) NOT NULL DEFAULT 0,
+    deleted_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE tickets (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    order_id BIGINT NULL,
+    subject VARCHAR(255) NOT NULL,
+    category VARCHAR(50),
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE ticket_messages (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    ticket_id BIGINT NOT NULL,
+    sender_role VARCHAR(20) NOT NULL,
+    sender_id BIGINT NOT NULL,
+    message TEXT NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id)
+);
+
+CREATE TABLE restock_requests (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    message TEXT,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE product_recommendations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    recommended_product_id BIGINT NOT NULL,
+    score INT NOT NULL,
+    computed_at DATETIME NOT NULL,
+    KEY idx_product (product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (recommended_product_id) REFERENCES products(id)
+);
+
+CREATE TABLE product_changes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    actor_id BIGINT NOT NULL,
+    actor_role VARCHAR(20) NOT NULL,
+    field VARCHAR(64) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at DATETIME NOT NULL,
+    KEY idx_product_changes_product (product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE related_products (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    related_product_id BIGINT NOT NULL,
+    score INT NOT NULL,
+    computed_at DATETIME NOT NULL,
+    KEY idx_related_product (product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (related_product_id) REFERENCES products(id)
+);
+
+CREATE TABLE wishlists (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE KEY uniq_user_product_variant (user_id, product_id, variant_id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE coupons (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL,
+    value DECIMAL(12,2) NOT NULL,
+    min_order_total DECIMAL(12,2) NOT NULL DEFAULT 0,
+    per_user_limit INT NOT NULL DEFAULT 0,
+    usage_limit INT NOT NULL DEFAULT 0,
+    used_count INT NOT NULL DEFAULT 0,
+    product_id BIGINT NULL,
+    category_id BIGINT NULL,
+    starts_at DATETIME NULL,
+    expires_at DATETIME NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    deleted_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (product_id) REFERENCES products(id),
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE coupon_usages (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    coupon_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    order_id BIGINT NULL,
+    used_at DATETIME NOT NULL,
+    FOREIGN KEY (coupon_id) REFERENCES coupons(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE checkout_rules (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rule_type VARCHAR(30) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE suppliers (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    contact_name VARCHAR(100),
+    phone VARCHAR(30),
+    email VARCHAR(255),
+    address TEXT,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE purchase_orders (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    supplier_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    expected_at DATETIME NULL,
+    notes TEXT,
+    has_discrepancy TINYINT(1) NOT NULL DEFAULT 0,
+    created_by BIGINT NOT NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(id)
+);
+
+CREATE TABLE purchase_order_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    purchase_order_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    quantity_ordered INT NOT NULL,
+    quantity_received INT NOT NULL DEFAULT 0,
+    unit_cost DECIMAL(12,2) NOT NULL DEFAULT 0,
+    FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE stock_movements (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    delta INT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    ref_id BIGINT NOT NULL DEFAULT 0,
+    actor_id BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    KEY idx_product (product_id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE pos_sales (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    cashier_id BIGINT NOT NULL,
+    total_price DECIMAL(12,2) NOT NULL,
+    payment_method VARCHAR(20) NOT NULL,
+    amount_paid DECIMAL(12,2) NOT NULL,
+    change_due DECIMAL(12,2) NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (cashier_id) REFERENCES employees(id)
+);
+
+CREATE TABLE pos_sale_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    sale_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    quantity INT NOT NULL,
+    price DECIMAL(12,2) NOT NULL,
+    FOREIGN KEY (sale_id) REFERENCES pos_sales(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE subscriptions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    interval_days INT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    next_run_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE subscription_items (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    quantity INT NOT NULL,
+    FOREIGN KEY (subscription_id) REFERENCES subscriptions(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE warehouses (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    location VARCHAR(255)
+);
+
+CREATE TABLE warehouse_stock (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    warehouse_id BIGINT NOT NULL,
+    product_id BIGINT NOT NULL,
+    variant_id BIGINT NULL,
+    stock INT NOT NULL DEFAULT 0,
+    UNIQUE KEY uniq_warehouse_product_variant (warehouse_id, product_id, variant_id),
+    FOREIGN KEY (warehouse_id) REFERENCES warehouses(id),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE heart_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    delta INT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    ref_id BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    KEY idx_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE login_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    user_agent VARCHAR(255) NOT NULL,
+    created_at DATETIME NOT NULL,
+    KEY idx_user_role (user_id, role)
+);
+
+CREATE TABLE otp_codes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    code VARCHAR(6) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used TINYINT(1) NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    KEY idx_phone (phone)
+);
+
+CREATE TABLE promotions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    promo_type VARCHAR(30) NOT NULL,
+    product_id BIGINT NULL,
+    category_id BIGINT NULL,
+    buy_qty INT NOT NULL DEFAULT 0,
+    get_qty INT NOT NULL DEFAULT 0,
+    percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+    threshold DECIMAL(12,2) NOT NULL DEFAULT 0,
+    gift_product_id BIGINT NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE order_promotions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    promotion_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    discount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    gift_product_id BIGINT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE fulfillments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at DATETIME NOT NULL,
+    KEY idx_order (order_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE deliveries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    fulfillment_id BIGINT NOT NULL UNIQUE,
+    driver_id BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'assigned',
+    proof_url VARCHAR(500) NULL,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (fulfillment_id) REFERENCES fulfillments(id),
+    FOREIGN KEY (driver_id) REFERENCES employees(id)
+);
+
+CREATE TABLE cod_settlements (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    collected_by BIGINT NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (order_id) REFERENCES orders(id),
+    FOREIGN KEY (collected_by) REFERENCES employees(id)
+);
+
+CREATE TABLE qris_payments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_id BIGINT NOT NULL,
+    external_id VARCHAR(100) NOT NULL UNIQUE,
+    qr_payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    KEY idx_order (order_id),
+    FOREIGN KEY (order_id) REFERENCES orders(id)
+);
+
+CREATE TABLE webhook_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    provider VARCHAR(30) NOT NULL,
+    event_id VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'received',
+    last_error TEXT NULL,
+    created_at DATETIME NOT NULL,
+    UNIQUE KEY uniq_provider_event (provider, event_id)
+);
+
+CREATE TABLE product_options (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    product_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    surcharge DECIMAL(12,2) NOT NULL DEFAULT 0,
+    required TINYINT(1) NOT NULL DEFAULT 0,
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
+
+CREATE TABLE cart_item_options (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    cart_item_id BIGINT NOT NULL,
+    option_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    surcharge DECIMAL(12,2) NOT NULL DEFAULT 0,
+    KEY idx_cart_item (cart_item_id),
+    FOREIGN KEY (cart_item_id) REFERENCES cart_items(id)
+);
+
+CREATE TABLE order_item_options (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    order_item_id BIGINT NOT NULL,
+    option_id BIGINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    surcharge DECIMAL(12,2) NOT NULL DEFAULT 0,
+    KEY idx_order_item (order_item_id),
+    FOREIGN KEY (order_item_id) REFERENCES order_items(id)
+);
+
+CREATE TABLE heart_policy_tiers (
+    hearts INT PRIMARY KEY,
+    duration_hours INT NOT NULL
+);
+
+INSERT INTO heart_policy_tiers (hearts, duration_hours) VALUES (3, 24), (2, 12), (1, 6);
+
+CREATE TABLE category_campaigns (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    category_id BIGINT NOT NULL,
+    percent DECIMAL(5,2) NOT NULL,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL,
+    KEY idx_category_range (category_id, starts_at, ends_at),
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE supplier_feeds (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    format VARCHAR(10) NOT NULL,
+    update_prices TINYINT(1) NOT NULL DEFAULT 0,
+    update_stock TINYINT(1) NOT NULL DEFAULT 0,
+    create_missing TINYINT(1) NOT NULL DEFAULT 0,
+    category_id BIGINT NULL,
+    interval_minutes INT NOT NULL DEFAULT 60,
+    enabled TINYINT(1) NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE feed_runs (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    feed_id BIGINT NOT NULL,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME NOT NULL,
+    created_count INT NOT NULL DEFAULT 0,
+    updated_count INT NOT NULL DEFAULT 0,
+    skipped_count INT NOT NULL DEFAULT 0,
+    error TEXT NULL,
+    KEY idx_feed (feed_id),
+    FOREIGN KEY (feed_id) REFERENCES supplier_feeds(id)
+);
+
+CREATE TABLE refresh_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at DATETIME NOT NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE revoked_tokens (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE password_resets (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    used TINYINT(1) NOT NULL DEFAULT 0,
+    expires_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE two_factor (
+    account_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    enabled TINYINT(1) NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    PRIMARY KEY (account_id, role)
+);
+
+CREATE TABLE two_factor_recovery_codes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    account_id INT NOT NULL,
+    role VARCHAR(20) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE api_keys (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    scopes VARCHAR(255) NOT NULL,
+    last_used_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE audit_logs (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NOT NULL,
+    actor_role VARCHAR(20) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    entity VARCHAR(30) NOT NULL,
+    entity_id INT NOT NULL,
+    before_json JSON NULL,
+    after_json JSON NULL,
+    created_at DATETIME NOT NULL
+);
+
+CREATE TABLE roles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(20) NOT NULL UNIQUE
+);
+
+CREATE TABLE permissions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(50) NOT NULL UNIQUE
+);
+
+CREATE TABLE role_permissions (
+    role_id INT NOT NULL,
+    permission_id INT NOT NULL,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+INSERT INTO roles (name) VALUES ('admin'), ('stocker'), ('cashier'), ('driver');
+INSERT INTO permissions (name) VALUES ('products:write'), ('categories:write'), ('stock:write');
+-- Admin bypasses permission checks in code; stockers keep their stock rights.
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name = 'stock:write' WHERE r.name = 'stocker';
+
+CREATE TABLE addresses (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    label VARCHAR(50) NOT NULL,
+    recipient VARCHAR(100) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    street VARCHAR(255) NOT NULL,
+    city VARCHAR(100) NOT NULL,
+    postal_code VARCHAR(10) NOT NULL,
+    is_default TINYINT(1) NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE user_activities (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    detail VARCHAR(255) NOT NULL,
+    ref_id BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    KEY idx_user_activities_user (user_id, id)
+);
+
+CREATE TABLE point_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    delta INT NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    ref_id BIGINT NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL,
+    KEY idx_point_events_user (user_id, id)
+);
+
+CREATE TABLE referrals (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    referrer_id BIGINT NOT NULL,
+    referee_id BIGINT NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    rewarded_at DATETIME NULL,
+    created_at DATETIME NOT NULL,
+    FOREIGN KEY (referrer_id) REFERENCES users(id),
+    FOREIGN KEY (referee_id) REFERENCES users(id)
+);
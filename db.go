@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+var db *sql.DB
+
+// replicaDB is a read-only replica pool; nil when no replica is configured.
+var replicaDB *sql.DB
+
+// ConnectDatabase opens the MySQL connection pool described by the config,
+// plus the replica pool when DB_REPLICA_HOST is set.
+func ConnectDatabase() error {
+	// loc=UTC keeps DATETIME scans and NOW()-free comparisons in one timezone.
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&loc=UTC",
+		config.DBUser, config.DBPass, config.DBHost, config.DBPort, config.DBName)
+	var err error
+	db, err = sql.Open("mysql", dsn)
+	if err != nil {
+		return err
+	}
+	db.SetMaxOpenConns(25)
+	db.SetMaxIdleConns(5)
+	if err := db.Ping(); err != nil {
+		return err
+	}
+	if config.DBReplicaHost != "" {
+		replicaDSN := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&loc=UTC",
+			config.DBUser, config.DBPass, config.DBReplicaHost, config.DBReplicaPort, config.DBName)
+		replicaDB, err = sql.Open("mysql", replicaDSN)
+		if err != nil {
+			return err
+		}
+		replicaDB.SetMaxOpenConns(25)
+		replicaDB.SetMaxIdleConns(5)
+		if err := replicaDB.Ping(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readOnlyDB returns the pool to use for replica-safe reads — catalog
+// browsing, search, and reports. Checkout and anything that reads its own
+// writes must stay on db: the replica can lag behind the primary.
+func readOnlyDB() *sql.DB {
+	if replicaDB != nil {
+		return replicaDB
+	}
+	return db
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerDeliveryRoutes(r *Router) {
+	r.POST("/api/v1/admin/fulfillments/:id/assign", RoleMiddleware("admin")(AssignDelivery))
+	r.GET("/api/v1/driver/deliveries", RoleMiddleware("driver")(GetMyDeliveries))
+	r.PATCH("/api/v1/driver/deliveries/:id/status", RoleMiddleware("driver")(UpdateDeliveryStatus))
+}
+
+// Delivery links one physical fulfillment to the driver carrying it.
+type Delivery struct {
+	ID            int64     `json:"id"`
+	FulfillmentID int64     `json:"fulfillment_id"`
+	DriverID      int64     `json:"driver_id"`
+	Status        string    `json:"status"`
+	ProofURL      *string   `json:"proof_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+var deliveryStatuses = map[string]bool{
+	"assigned":  true,
+	"picked_up": true,
+	"delivered": true,
+	"failed":    true,
+}
+
+// AssignDelivery links a physical fulfillment to a driver and flips the
+// fulfillment to packed.
+func AssignDelivery(w http.ResponseWriter, r *http.Request) {
+	fulfillmentID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		DriverID int64 `json:"driver_id"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.DriverID == 0 {
+		respondError(w, http.StatusBadRequest, "driver_id wajib diisi")
+		return
+	}
+	var fType string
+	err = db.QueryRow("SELECT type FROM fulfillments WHERE id = ?", fulfillmentID).Scan(&fType)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "fulfillment tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if fType != "physical" {
+		respondError(w, http.StatusBadRequest, "hanya fulfillment fisik yang bisa dikirim driver")
+		return
+	}
+	var role string
+	err = db.QueryRow("SELECT role FROM employees WHERE id = ?", req.DriverID).Scan(&role)
+	if err == sql.ErrNoRows || (err == nil && role != "driver") {
+		respondError(w, http.StatusBadRequest, "driver tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	now := nowUTC()
+	res, err := db.Exec(
+		"INSERT INTO deliveries (fulfillment_id, driver_id, status, created_at, updated_at) VALUES (?, ?, 'assigned', ?, ?)",
+		fulfillmentID, req.DriverID, now, now)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "fulfillment ini sudah punya driver")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	db.Exec("UPDATE fulfillments SET status = 'packed' WHERE id = ? AND status = 'pending'", fulfillmentID)
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "driver berhasil ditugaskan"})
+}
+
+// GetMyDeliveries lists the authenticated driver's open deliveries.
+func GetMyDeliveries(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		`SELECT id, fulfillment_id, driver_id, status, proof_url, created_at, updated_at
+		 FROM deliveries WHERE driver_id = ? AND status IN ('assigned', 'picked_up')
+		 ORDER BY id`, currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	deliveries := []Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		deliveries = append(deliveries, d)
+	}
+	respondJSON(w, http.StatusOK, deliveries)
+}
+
+func scanDelivery(row interface{ Scan(...any) error }) (Delivery, error) {
+	var (
+		d     Delivery
+		proof sql.NullString
+	)
+	err := row.Scan(&d.ID, &d.FulfillmentID, &d.DriverID, &d.Status, &proof,
+		&d.CreatedAt, &d.UpdatedAt)
+	if proof.Valid {
+		d.ProofURL = &proof.String
+	}
+	return d, err
+}
+
+// UpdateDeliveryStatus lets the assigned driver move a delivery forward;
+// marking it delivered requires a proof-of-delivery photo URL and cascades to
+// the fulfillment plus a customer notification.
+func UpdateDeliveryStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Status   string  `json:"status"`
+		ProofURL *string `json:"proof_url"`
+	}
+	if err := decodeBody(r, &req); err != nil || !deliveryStatuses[req.Status] {
+		respondError(w, http.StatusBadRequest, "status tidak dikenal")
+		return
+	}
+	if req.Status == "delivered" && (req.ProofURL == nil || *req.ProofURL == "") {
+		respondError(w, http.StatusBadRequest, "bukti pengiriman (proof_url) wajib untuk status delivered")
+		return
+	}
+	var fulfillmentID int64
+	err = db.QueryRow("SELECT fulfillment_id FROM deliveries WHERE id = ? AND driver_id = ?",
+		id, currentUserID(r)).Scan(&fulfillmentID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "pengiriman tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, err := db.Exec(
+		"UPDATE deliveries SET status = ?, proof_url = COALESCE(?, proof_url), updated_at = ? WHERE id = ?",
+		req.Status, req.ProofURL, nowUTC(), id); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Keep the customer-facing fulfillment status in step with the driver.
+	switch req.Status {
+	case "picked_up":
+		db.Exec("UPDATE fulfillments SET status = 'shipped' WHERE id = ?", fulfillmentID)
+	case "delivered":
+		db.Exec("UPDATE fulfillments SET status = 'delivered' WHERE id = ?", fulfillmentID)
+	}
+	var userID int64
+	if err := db.QueryRow(
+		`SELECT o.user_id FROM fulfillments f JOIN orders o ON o.id = f.order_id WHERE f.id = ?`,
+		fulfillmentID).Scan(&userID); err == nil {
+		switch req.Status {
+		case "picked_up":
+			createNotification(userID, "Pesanan dibawa kurir", "Pesanan kamu sedang dalam perjalanan.")
+		case "delivered":
+			createNotification(userID, "Pesanan sampai", "Pesanan kamu sudah diterima. Terima kasih!")
+		case "failed":
+			createNotification(userID, "Pengiriman gagal", "Kurir gagal mengantar pesanan kamu, kami akan menghubungi kamu.")
+		}
+	}
+	respondMessage(w, http.StatusOK, "status pengiriman diupdate")
+}
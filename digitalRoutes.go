@@ -0,0 +1,303 @@
+package main
+
+import (
+	"crypto/hmac"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func registerDigitalRoutes(r *Router) {
+	r.GET("/api/v1/admin/products/:id/files", RoleMiddleware("admin")(GetProductFiles))
+	r.POST("/api/v1/admin/products/:id/files", RoleMiddleware("admin")(CreateProductFile))
+	r.DELETE("/api/v1/admin/files/:id", RoleMiddleware("admin")(DeleteProductFile))
+	r.POST("/api/v1/admin/products/:id/license-keys", RoleMiddleware("admin")(CreateLicenseKeys))
+	r.GET("/api/v1/orders/:id/downloads", AuthMiddleware(GetOrderDownloads))
+	r.GET("/api/v1/downloads/:id", DownloadFile)
+}
+
+// ProductFile is a downloadable asset attached to a digital product.
+type ProductFile struct {
+	ID        int64     `json:"id"`
+	ProductID int64     `json:"product_id"`
+	Name      string    `json:"name"`
+	FileURL   string    `json:"file_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// downloadURLTTL is how long a signed download link stays valid.
+const downloadURLTTL = 15 * time.Minute
+
+// GetProductFiles lists the files of a digital product.
+func GetProductFiles(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	rows, err := db.Query(
+		"SELECT id, product_id, name, file_url, created_at FROM product_files WHERE product_id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	files := []ProductFile{}
+	for rows.Next() {
+		var f ProductFile
+		if err := rows.Scan(&f.ID, &f.ProductID, &f.Name, &f.FileURL, &f.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		files = append(files, f)
+	}
+	respondJSON(w, http.StatusOK, files)
+}
+
+// CreateProductFile attaches a downloadable file to a digital product.
+func CreateProductFile(w http.ResponseWriter, r *http.Request) {
+	productID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Name    string `json:"name"`
+		FileURL string `json:"file_url"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Name == "" || req.FileURL == "" {
+		respondError(w, http.StatusBadRequest, "name dan file_url wajib diisi")
+		return
+	}
+	var isDigital bool
+	err = db.QueryRow("SELECT is_digital FROM products WHERE id = ?", productID).Scan(&isDigital)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !isDigital {
+		respondError(w, http.StatusBadRequest, "produk ini bukan produk digital")
+		return
+	}
+	res, err := db.Exec(
+		"INSERT INTO product_files (product_id, name, file_url, created_at) VALUES (?, ?, ?, ?)",
+		productID, req.Name, req.FileURL, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "file berhasil ditambahkan"})
+}
+
+// DeleteProductFile removes a downloadable asset.
+func DeleteProductFile(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("DELETE FROM product_files WHERE id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "file tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "file berhasil dihapus")
+}
+
+// CreateLicenseKeys adds a batch of unassigned license keys to the pool.
+func CreateLicenseKeys(w http.ResponseWriter, r *http.Request) {
+	productID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Keys []string `json:"keys"`
+	}
+	if err := decodeBody(r, &req); err != nil || len(req.Keys) == 0 {
+		respondError(w, http.StatusBadRequest, "keys wajib diisi")
+		return
+	}
+	for _, key := range req.Keys {
+		if _, err := db.Exec(
+			"INSERT INTO license_keys (product_id, license_key, created_at) VALUES (?, ?, ?)",
+			productID, key, nowUTC()); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"added":   len(req.Keys),
+		"message": "license keys berhasil ditambahkan",
+	})
+}
+
+// assignLicenseKeys hands out one unused key per unit of every digital line in
+// a freshly paid order.
+func assignLicenseKeys(orderID int64) {
+	rows, err := db.Query(
+		`SELECT oi.product_id, oi.quantity FROM order_items oi
+		 JOIN products p ON p.id = oi.product_id
+		 WHERE oi.order_id = ? AND p.is_digital = 1`, orderID)
+	if err != nil {
+		log.Printf("gagal ambil item digital order %d: %v", orderID, err)
+		return
+	}
+	type digitalLine struct {
+		productID int64
+		quantity  int
+	}
+	var lines []digitalLine
+	for rows.Next() {
+		var l digitalLine
+		if err := rows.Scan(&l.productID, &l.quantity); err != nil {
+			rows.Close()
+			log.Printf("gagal scan item digital order %d: %v", orderID, err)
+			return
+		}
+		lines = append(lines, l)
+	}
+	rows.Close()
+	for _, l := range lines {
+		for i := 0; i < l.quantity; i++ {
+			res, err := db.Exec(
+				`UPDATE license_keys SET order_id = ?, assigned_at = ?
+				 WHERE product_id = ? AND order_id IS NULL LIMIT 1`,
+				orderID, nowUTC(), l.productID)
+			if err != nil {
+				log.Printf("gagal assign license key produk %d: %v", l.productID, err)
+				return
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				log.Printf("stok license key produk %d habis untuk order %d", l.productID, orderID)
+				break
+			}
+		}
+	}
+}
+
+// signDownload builds the signature for a file download link.
+func signDownload(fileID, orderID, exp int64) string {
+	return signHS256(fmt.Sprintf("download:%d:%d:%d", fileID, orderID, exp))
+}
+
+// GetOrderDownloads returns signed, expiring download URLs and license keys
+// for the digital items of a paid order.
+func GetOrderDownloads(w http.ResponseWriter, r *http.Request) {
+	orderID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var status string
+	err = db.QueryRow("SELECT status FROM orders WHERE id = ? AND user_id = ?",
+		orderID, currentUserID(r)).Scan(&status)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if status != "paid" && status != "delivered" {
+		respondError(w, http.StatusBadRequest, "download hanya tersedia setelah order dibayar")
+		return
+	}
+	exp := nowUTC().Add(downloadURLTTL).Unix()
+	downloads := []map[string]any{}
+	rows, err := db.Query(
+		`SELECT pf.id, pf.name FROM product_files pf
+		 JOIN order_items oi ON oi.product_id = pf.product_id
+		 WHERE oi.order_id = ?`, orderID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for rows.Next() {
+		var (
+			fileID int64
+			name   string
+		)
+		if err := rows.Scan(&fileID, &name); err != nil {
+			rows.Close()
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		downloads = append(downloads, map[string]any{
+			"name": name,
+			"url": fmt.Sprintf("/api/v1/downloads/%d?order=%d&exp=%d&sig=%s",
+				fileID, orderID, exp, signDownload(fileID, orderID, exp)),
+		})
+	}
+	rows.Close()
+	licenses := []map[string]any{}
+	rows, err = db.Query(
+		"SELECT product_id, license_key FROM license_keys WHERE order_id = ?", orderID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for rows.Next() {
+		var (
+			productID int64
+			key       string
+		)
+		if err := rows.Scan(&productID, &key); err != nil {
+			rows.Close()
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		licenses = append(licenses, map[string]any{"product_id": productID, "license_key": key})
+	}
+	rows.Close()
+	respondJSON(w, http.StatusOK, map[string]any{
+		"downloads":    downloads,
+		"license_keys": licenses,
+		"expires_at":   exp,
+	})
+}
+
+// DownloadFile validates a signed link and hands back the file location.
+func DownloadFile(w http.ResponseWriter, r *http.Request) {
+	fileID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	orderID, err1 := strconv.ParseInt(r.URL.Query().Get("order"), 10, 64)
+	exp, err2 := strconv.ParseInt(r.URL.Query().Get("exp"), 10, 64)
+	sig := r.URL.Query().Get("sig")
+	if err1 != nil || err2 != nil || sig == "" {
+		respondError(w, http.StatusBadRequest, "link download tidak lengkap")
+		return
+	}
+	if !hmac.Equal([]byte(sig), []byte(signDownload(fileID, orderID, exp))) {
+		respondError(w, http.StatusForbidden, "link download tidak valid")
+		return
+	}
+	if nowUTC().Unix() > exp {
+		respondError(w, http.StatusForbidden, "link download sudah kadaluarsa")
+		return
+	}
+	var fileURL string
+	err = db.QueryRow("SELECT file_url FROM product_files WHERE id = ?", fileID).Scan(&fileURL)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "file tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	http.Redirect(w, r, fileURL, http.StatusFound)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// fieldSet parses the ?fields= parameter into a lookup set, or nil when the
+// client did not ask for a sparse response.
+func fieldSet(r *http.Request) map[string]bool {
+	raw := r.URL.Query().Get("fields")
+	if raw == "" {
+		return nil
+	}
+	set := map[string]bool{}
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			set[f] = true
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+	return set
+}
+
+// pruneFields reduces v (a struct, slice, or map destined for JSON) to only
+// the requested top-level fields. With a nil set it returns v untouched.
+func pruneFields(v any, fields map[string]bool) any {
+	if fields == nil {
+		return v
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return v
+	}
+	return pruneValue(decoded, fields)
+}
+
+func pruneValue(v any, fields map[string]bool) any {
+	switch typed := v.(type) {
+	case []any:
+		for i, item := range typed {
+			typed[i] = pruneValue(item, fields)
+		}
+		return typed
+	case map[string]any:
+		for key := range typed {
+			if !fields[key] {
+				delete(typed, key)
+			}
+		}
+		return typed
+	default:
+		return v
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerFulfillmentRoutes(r *Router) {
+	r.GET("/api/v1/admin/fulfillments", RoleMiddleware("admin")(GetFulfillments))
+	r.PATCH("/api/v1/admin/fulfillments/:id/status", RoleMiddleware("admin")(UpdateFulfillmentStatus))
+}
+
+// Fulfillment is one shippable (or deliverable) slice of an order. Orders
+// mixing physical and digital items get one fulfillment per type so each can
+// move through its own status independently.
+type Fulfillment struct {
+	ID        int64       `json:"id"`
+	OrderID   int64       `json:"order_id"`
+	Type      string      `json:"type"`
+	Status    string      `json:"status"`
+	CreatedAt time.Time   `json:"created_at"`
+	Items     []OrderItem `json:"items,omitempty"`
+	// Delivery is set once a driver has been assigned, so customers can track
+	// progress.
+	Delivery *Delivery `json:"delivery,omitempty"`
+}
+
+var fulfillmentStatuses = map[string]bool{
+	"pending":   true,
+	"packed":    true,
+	"shipped":   true,
+	"delivered": true,
+	"cancelled": true,
+}
+
+// createFulfillment inserts one fulfillment for an order inside the caller's
+// transaction and returns its id.
+func createFulfillment(q dbtx, orderID int64, fType string) (int64, error) {
+	res, err := q.Exec(
+		"INSERT INTO fulfillments (order_id, type, status, created_at) VALUES (?, ?, 'pending', ?)",
+		orderID, fType, nowUTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// fetchOrderFulfillments returns an order's fulfillments with their items
+// grouped underneath.
+func fetchOrderFulfillments(orderID int64) ([]Fulfillment, error) {
+	rows, err := db.Query(
+		"SELECT id, order_id, type, status, created_at FROM fulfillments WHERE order_id = ?", orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	fulfillments := []Fulfillment{}
+	for rows.Next() {
+		var f Fulfillment
+		if err := rows.Scan(&f.ID, &f.OrderID, &f.Type, &f.Status, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		fulfillments = append(fulfillments, f)
+	}
+	for i := range fulfillments {
+		d, err := scanDelivery(db.QueryRow(
+			`SELECT id, fulfillment_id, driver_id, status, proof_url, created_at, updated_at
+			 FROM deliveries WHERE fulfillment_id = ?`, fulfillments[i].ID))
+		if err == nil {
+			fulfillments[i].Delivery = &d
+		} else if err != sql.ErrNoRows {
+			return nil, err
+		}
+		itemRows, err := db.Query(
+			`SELECT id, order_id, product_id, variant_id, bundle_id, quantity, price, is_preorder
+			 FROM order_items WHERE fulfillment_id = ?`, fulfillments[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		fulfillments[i].Items = []OrderItem{}
+		for itemRows.Next() {
+			var (
+				it                             OrderItem
+				productID, variantID, bundleID sql.NullInt64
+			)
+			if err := itemRows.Scan(&it.ID, &it.OrderID, &productID, &variantID, &bundleID,
+				&it.Quantity, &it.Price, &it.IsPreorder); err != nil {
+				itemRows.Close()
+				return nil, err
+			}
+			if productID.Valid {
+				it.ProductID = &productID.Int64
+			}
+			if variantID.Valid {
+				it.VariantID = &variantID.Int64
+			}
+			if bundleID.Valid {
+				it.BundleID = &bundleID.Int64
+			}
+			fulfillments[i].Items = append(fulfillments[i].Items, it)
+		}
+		itemRows.Close()
+	}
+	return fulfillments, nil
+}
+
+// GetFulfillments lists fulfillments for admins, optionally filtered with
+// ?status= and ?type=.
+func GetFulfillments(w http.ResponseWriter, r *http.Request) {
+	query := "SELECT id, order_id, type, status, created_at FROM fulfillments WHERE 1=1"
+	args := []any{}
+	if s := r.URL.Query().Get("status"); s != "" {
+		query += " AND status = ?"
+		args = append(args, s)
+	}
+	if t := r.URL.Query().Get("type"); t != "" {
+		query += " AND type = ?"
+		args = append(args, t)
+	}
+	query += " ORDER BY id DESC LIMIT 100"
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	fulfillments := []Fulfillment{}
+	for rows.Next() {
+		var f Fulfillment
+		if err := rows.Scan(&f.ID, &f.OrderID, &f.Type, &f.Status, &f.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		fulfillments = append(fulfillments, f)
+	}
+	respondJSON(w, http.StatusOK, fulfillments)
+}
+
+// UpdateFulfillmentStatus moves one fulfillment through its lifecycle and
+// notifies the customer when their shipment goes out or arrives.
+func UpdateFulfillmentStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Status string `json:"status"`
+	}
+	if err := decodeBody(r, &req); err != nil || !fulfillmentStatuses[req.Status] {
+		respondError(w, http.StatusBadRequest, "status tidak dikenal")
+		return
+	}
+	var orderID int64
+	err = db.QueryRow("SELECT order_id FROM fulfillments WHERE id = ?", id).Scan(&orderID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "fulfillment tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, err := db.Exec("UPDATE fulfillments SET status = ? WHERE id = ?", req.Status, id); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if req.Status == "shipped" || req.Status == "delivered" {
+		var userID int64
+		if err := db.QueryRow("SELECT user_id FROM orders WHERE id = ?", orderID).
+			Scan(&userID); err == nil {
+			title := "Pesanan dikirim"
+			message := "Sebagian pesanan kamu sudah dikirim."
+			if req.Status == "delivered" {
+				title = "Pesanan sampai"
+				message = "Sebagian pesanan kamu sudah sampai tujuan."
+			}
+			createNotification(userID, title, message)
+		}
+	}
+	respondMessage(w, http.StatusOK, "status fulfillment diupdate")
+}
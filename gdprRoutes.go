@@ -0,0 +1,296 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func registerGdprRoutes(r *Router) {
+	r.GET("/api/v1/users/me/data-export", AuthMiddleware(ExportMyData))
+	r.DELETE("/api/v1/users/me/erase", AuthMiddleware(EraseMyData))
+	// Shorter aliases under /me; DELETE /me additionally soft-deletes the row.
+	r.GET("/api/v1/me/export", AuthMiddleware(ExportMyData))
+	r.DELETE("/api/v1/me", AuthMiddleware(DeleteMyAccount))
+}
+
+// ExportMyData returns everything we store about the authenticated user in
+// one JSON document: profile, orders with items, cart, notifications, and
+// login history.
+func ExportMyData(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	var (
+		u     User
+		phone sql.NullString
+	)
+	err := db.QueryRow("SELECT id, username, email, phone, hearts, created_at FROM users WHERE id = ?",
+		userID).Scan(&u.ID, &u.Username, &u.Email, &phone, &u.Hearts, &u.CreatedAt)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if phone.Valid {
+		u.Phone = &phone.String
+	}
+
+	orderRows, err := db.Query(
+		"SELECT "+orderColumns+" FROM orders WHERE user_id = ? ORDER BY id", userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer orderRows.Close()
+	orders := []Order{}
+	for orderRows.Next() {
+		o, err := scanOrder(orderRows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		orders = append(orders, o)
+	}
+	for i := range orders {
+		items, err := fetchOrderItems(orders[i].ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		orders[i].Items = items
+	}
+
+	cartID, err := getOrCreateCart(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	cartRows, err := db.Query(
+		"SELECT id, cart_id, product_id, variant_id, bundle_id, quantity, price FROM cart_items WHERE cart_id = ?",
+		cartID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer cartRows.Close()
+	cartItems := []CartItem{}
+	for cartRows.Next() {
+		var (
+			it                             CartItem
+			productID, variantID, bundleID sql.NullInt64
+		)
+		if err := cartRows.Scan(&it.ID, &it.CartID, &productID, &variantID, &bundleID,
+			&it.Quantity, &it.Price); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if productID.Valid {
+			it.ProductID = &productID.Int64
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		if bundleID.Valid {
+			it.BundleID = &bundleID.Int64
+		}
+		cartItems = append(cartItems, it)
+	}
+
+	notifRows, err := db.Query(
+		"SELECT "+notificationColumns+" FROM notifications WHERE user_id = ?"+notDeleted(""), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer notifRows.Close()
+	notifications := []Notification{}
+	for notifRows.Next() {
+		n, err := scanNotification(notifRows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		notifications = append(notifications, n)
+	}
+
+	loginRows, err := db.Query(
+		"SELECT ip, user_agent, created_at FROM login_events WHERE user_id = ? AND role = 'user' ORDER BY id",
+		userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer loginRows.Close()
+	type loginExport struct {
+		IP        string `json:"ip"`
+		UserAgent string `json:"user_agent"`
+		CreatedAt string `json:"created_at"`
+	}
+	logins := []loginExport{}
+	for loginRows.Next() {
+		var (
+			le loginExport
+			at time.Time
+		)
+		if err := loginRows.Scan(&le.IP, &le.UserAgent, &at); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		le.CreatedAt = displayTime(at)
+		logins = append(logins, le)
+	}
+
+	resRows, err := db.Query(
+		"SELECT "+reservationColumns+" FROM stock_reservations WHERE user_id = ? ORDER BY id", userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer resRows.Close()
+	reservations := []StockReservation{}
+	for resRows.Next() {
+		sr, err := scanReservation(resRows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		reservations = append(reservations, sr)
+	}
+
+	addrRows, err := db.Query(
+		`SELECT id, label, recipient, phone, street, city, postal_code, is_default, created_at
+		 FROM addresses WHERE user_id = ? ORDER BY id`, userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer addrRows.Close()
+	addresses := []Address{}
+	for addrRows.Next() {
+		var (
+			a       Address
+			created time.Time
+		)
+		if err := addrRows.Scan(&a.ID, &a.Label, &a.Recipient, &a.Phone, &a.Street,
+			&a.City, &a.PostalCode, &a.IsDefault, &created); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		a.CreatedAt = displayTime(created)
+		addresses = append(addresses, a)
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=data-export-%d.json", userID))
+	respondJSON(w, http.StatusOK, map[string]any{
+		"profile":       u,
+		"orders":        orders,
+		"cart_items":    cartItems,
+		"notifications": notifications,
+		"logins":        logins,
+		"reservations":  reservations,
+		"addresses":     addresses,
+	})
+}
+
+// EraseMyData anonymizes the account after re-checking the password. Orders
+// and their accounting rows are kept, but every personally identifying field
+// is overwritten and side data (cart, notifications, login history, OTP
+// codes) is removed. The erased account can no longer log in.
+func EraseMyData(w http.ResponseWriter, r *http.Request) {
+	eraseAccount(w, r, false)
+}
+
+// DeleteMyAccount is the erase flow plus a deleted_at stamp, so the row also
+// disappears from admin listings like any other soft-deleted entity.
+func DeleteMyAccount(w http.ResponseWriter, r *http.Request) {
+	eraseAccount(w, r, true)
+}
+
+func eraseAccount(w http.ResponseWriter, r *http.Request, markDeleted bool) {
+	userID := currentUserID(r)
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Password == "" {
+		respondError(w, http.StatusBadRequest, "password wajib diisi untuk konfirmasi")
+		return
+	}
+	var (
+		hashed string
+		phone  sql.NullString
+	)
+	if err := db.QueryRow("SELECT password, phone FROM users WHERE id = ?", userID).
+		Scan(&hashed, &phone); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !checkPassword(hashed, req.Password) {
+		respondError(w, http.StatusUnauthorized, "password salah")
+		return
+	}
+	var open int
+	if err := db.QueryRow("SELECT COUNT(*) FROM orders WHERE user_id = ? AND status = 'waitToBuy'",
+		userID).Scan(&open); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if open > 0 {
+		respondError(w, http.StatusConflict, "masih ada order yang menunggu pembayaran, selesaikan atau biarkan kadaluarsa dulu")
+		return
+	}
+	err := WithTx(func(tx *sql.Tx) error {
+		// An impossible hash value blocks any future login attempt.
+		if _, err := tx.Exec(
+			`UPDATE users SET username = ?, email = ?, phone = NULL, wa_opt_in = 0, password = 'erased'
+			 WHERE id = ?`,
+			fmt.Sprintf("deleted-user-%d", userID), fmt.Sprintf("deleted-%d@anon.invalid", userID),
+			userID); err != nil {
+			return err
+		}
+		if markDeleted {
+			if _, err := tx.Exec("UPDATE users SET deleted_at = ? WHERE id = ?",
+				nowUTC(), userID); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.Exec(
+			`DELETE cio FROM cart_item_options cio
+			 JOIN cart_items ci ON ci.id = cio.cart_item_id
+			 JOIN carts c ON c.id = ci.cart_id WHERE c.user_id = ?`, userID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(
+			"DELETE ci FROM cart_items ci JOIN carts c ON c.id = ci.cart_id WHERE c.user_id = ?",
+			userID); err != nil {
+			return err
+		}
+		for _, stmt := range []string{
+			"UPDATE carts SET total_price = 0 WHERE user_id = ?",
+			"DELETE FROM notifications WHERE user_id = ?",
+			"DELETE FROM login_events WHERE user_id = ? AND role = 'user'",
+			"DELETE FROM wishlists WHERE user_id = ?",
+			"DELETE FROM restock_requests WHERE user_id = ?",
+			"DELETE FROM addresses WHERE user_id = ?",
+			"DELETE FROM user_activities WHERE user_id = ?",
+		} {
+			if _, err := tx.Exec(stmt, userID); err != nil {
+				return err
+			}
+		}
+		if phone.Valid {
+			if _, err := tx.Exec("DELETE FROM otp_codes WHERE phone = ?", phone.String); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Active sessions on other devices die with the account.
+	if err := revokeRefreshTokens(userID, "user"); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "data pribadi kamu sudah dihapus")
+}
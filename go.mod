@@ -0,0 +1,3 @@
+module github.com/michellaanjani/UTS-PPT
+
+go 1.21
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+func registerGoogleAuthRoutes(r *Router) {
+	r.POST("/api/v1/auth/google", GoogleLogin)
+}
+
+var oauthClient = &http.Client{Timeout: 10 * time.Second}
+
+type googleUserInfo struct {
+	Sub           string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+// exchangeGoogleCode trades the authorization code for tokens and fetches the
+// profile; the endpoint URLs live in config so tests and staging can point at
+// a stub.
+func exchangeGoogleCode(code string) (*googleUserInfo, error) {
+	form := url.Values{
+		"code":          {code},
+		"client_id":     {config.GoogleClientID},
+		"client_secret": {config.GoogleClientSecret},
+		"redirect_uri":  {config.GoogleRedirectURL},
+		"grant_type":    {"authorization_code"},
+	}
+	resp, err := oauthClient.PostForm(config.GoogleTokenURL, form)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google token endpoint menjawab status %d", resp.StatusCode)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil || tok.AccessToken == "" {
+		return nil, fmt.Errorf("response token google tidak valid")
+	}
+	req, err := http.NewRequest(http.MethodGet, config.GoogleUserinfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	infoResp, err := oauthClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer infoResp.Body.Close()
+	if infoResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google userinfo endpoint menjawab status %d", infoResp.StatusCode)
+	}
+	var info googleUserInfo
+	if err := json.NewDecoder(infoResp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GoogleLogin exchanges an OAuth authorization code for our own JWT. Existing
+// accounts are matched by google_id first, then linked by email; anyone else
+// gets a fresh user row with no usable password.
+func GoogleLogin(w http.ResponseWriter, r *http.Request) {
+	if !requireAuthAttempt(w, r) {
+		return
+	}
+	if config.GoogleClientID == "" {
+		respondError(w, http.StatusNotImplemented, "login google belum dikonfigurasi")
+		return
+	}
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Code == "" {
+		respondError(w, http.StatusBadRequest, "code wajib diisi")
+		return
+	}
+	info, err := exchangeGoogleCode(req.Code)
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "kode google tidak valid: "+err.Error())
+		return
+	}
+	if info.Sub == "" || info.Email == "" || !info.EmailVerified {
+		respondError(w, http.StatusUnauthorized, "akun google tidak bisa dipakai")
+		return
+	}
+	email := strings.ToLower(info.Email)
+	var id int64
+	err = db.QueryRow("SELECT id FROM users WHERE google_id = ?", info.Sub).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = db.QueryRow("SELECT id FROM users WHERE email = ?", email).Scan(&id)
+		if err == nil {
+			_, err = db.Exec("UPDATE users SET google_id = ?, is_verified = 1 WHERE id = ?", info.Sub, id)
+		} else if err == sql.ErrNoRows {
+			id, err = createGoogleUser(info.Sub, email, info.Name)
+		}
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	recordLogin(id, "user", r)
+	respondWithToken(w, r, id, "user", false)
+}
+
+func createGoogleUser(sub, email, name string) (int64, error) {
+	username := strings.Split(email, "@")[0]
+	if name != "" {
+		username = strings.ReplaceAll(strings.ToLower(name), " ", ".")
+	}
+	// No password login for this account: the stored value can never match a
+	// pbkdf2 hash.
+	raw := make([]byte, 8)
+	if _, err := rand.Read(raw); err != nil {
+		return 0, err
+	}
+	res, err := db.Exec(
+		"INSERT INTO users (username, email, password, google_id, hearts, is_verified, created_at) VALUES (?, ?, 'oauth', ?, 3, 1, ?)",
+		username, email, sub, nowUTC())
+	if isDuplicateKeyError(err) {
+		// Username collision: retry once with a random suffix.
+		res, err = db.Exec(
+			"INSERT INTO users (username, email, password, google_id, hearts, is_verified, created_at) VALUES (?, ?, 'oauth', ?, 3, 1, ?)",
+			username+"-"+hex.EncodeToString(raw[:3]), email, sub, nowUTC())
+	}
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerHeartPolicyRoutes(r *Router) {
+	r.GET("/api/v1/admin/settings/heart-policy", RoleMiddleware("admin")(GetHeartPolicy))
+	r.PUT("/api/v1/admin/settings/heart-policy", RoleMiddleware("admin")(UpdateHeartPolicy))
+}
+
+// HeartTier maps a hearts balance to how long checkout holds stock for it.
+type HeartTier struct {
+	Hearts        int `json:"hearts"`
+	DurationHours int `json:"duration_hours"`
+}
+
+// defaultHeartTiers is the policy used when no tiers have been configured.
+var defaultHeartTiers = []HeartTier{
+	{Hearts: 3, DurationHours: 24},
+	{Hearts: 2, DurationHours: 12},
+	{Hearts: 1, DurationHours: 6},
+}
+
+// heartTiers loads the configured tiers, falling back to the defaults when
+// the table is empty or unreadable so checkout never goes down over policy.
+func heartTiers() []HeartTier {
+	rows, err := db.Query("SELECT hearts, duration_hours FROM heart_policy_tiers ORDER BY hearts DESC")
+	if err != nil {
+		log.Printf("gagal membaca heart policy, pakai default: %v", err)
+		return defaultHeartTiers
+	}
+	defer rows.Close()
+	tiers := []HeartTier{}
+	for rows.Next() {
+		var t HeartTier
+		if err := rows.Scan(&t.Hearts, &t.DurationHours); err != nil {
+			log.Printf("gagal scan heart policy, pakai default: %v", err)
+			return defaultHeartTiers
+		}
+		tiers = append(tiers, t)
+	}
+	if len(tiers) == 0 {
+		return defaultHeartTiers
+	}
+	return tiers
+}
+
+// reservationDuration returns how long a user with the given hearts balance
+// may hold reserved stock. ok is false when the balance has no tier, which
+// blocks checkout entirely.
+func reservationDuration(hearts int) (time.Duration, bool) {
+	for _, t := range heartTiers() {
+		if t.Hearts == hearts {
+			return time.Duration(t.DurationHours) * time.Hour, true
+		}
+	}
+	return 0, false
+}
+
+// GetHeartPolicy returns the active hearts-to-duration tiers.
+func GetHeartPolicy(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, heartTiers())
+}
+
+// UpdateHeartPolicy replaces the tiers, so the hold windows can be tuned
+// without a redeploy.
+func UpdateHeartPolicy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Tiers []HeartTier `json:"tiers"`
+	}
+	if err := decodeBody(r, &req); err != nil || len(req.Tiers) == 0 {
+		respondError(w, http.StatusBadRequest, "tiers wajib diisi")
+		return
+	}
+	seen := map[int]bool{}
+	for _, t := range req.Tiers {
+		if t.Hearts < 1 || t.Hearts > maxHearts {
+			respondError(w, http.StatusBadRequest, "hearts harus antara 1 dan 3")
+			return
+		}
+		if t.DurationHours < 1 {
+			respondError(w, http.StatusBadRequest, "duration_hours minimal 1")
+			return
+		}
+		if seen[t.Hearts] {
+			respondError(w, http.StatusBadRequest, "tier hearts yang sama ditulis dua kali")
+			return
+		}
+		seen[t.Hearts] = true
+	}
+	err := WithTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM heart_policy_tiers"); err != nil {
+			return err
+		}
+		for _, t := range req.Tiers {
+			if _, err := tx.Exec(
+				"INSERT INTO heart_policy_tiers (hearts, duration_hours) VALUES (?, ?)",
+				t.Hearts, t.DurationHours); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "heart policy diupdate")
+}
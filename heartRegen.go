@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// StartHeartRegenWorker periodically gives one heart back to users who have
+// behaved for a while: no heart loss in the last HEART_REGEN_DAYS days, and
+// no automatic regen inside that same window. Without this, a user who
+// stopped letting orders expire would stay at a reduced checkout timer
+// forever unless an admin intervened.
+func StartHeartRegenWorker() {
+	if config.HeartRegenDays <= 0 {
+		return
+	}
+	go func() {
+		for range time.Tick(time.Hour) {
+			regenerateHearts()
+		}
+	}()
+}
+
+func regenerateHearts() {
+	cutoff := nowUTC().Add(-time.Duration(config.HeartRegenDays) * 24 * time.Hour)
+	rows, err := db.Query(
+		`SELECT u.id FROM users u
+		 WHERE u.hearts < ? AND u.is_banned = 0 AND u.deleted_at IS NULL
+		   AND NOT EXISTS (
+		       SELECT 1 FROM heart_events e
+		       WHERE e.user_id = u.id AND e.created_at > ?
+		         AND (e.delta < 0 OR e.reason = 'auto_regen'))`,
+		maxHearts, cutoff)
+	if err != nil {
+		log.Printf("gagal cek kandidat regen heart: %v", err)
+		return
+	}
+	var userIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			log.Printf("gagal scan kandidat regen heart: %v", err)
+			return
+		}
+		userIDs = append(userIDs, id)
+	}
+	rows.Close()
+	for _, id := range userIDs {
+		changed, err := adjustHearts(db, id, 1, "auto_regen", 0)
+		if err != nil {
+			log.Printf("gagal regen heart user %d: %v", id, err)
+			continue
+		}
+		if changed {
+			createNotification(id, "Heart dipulihkan",
+				"1 heart kembali karena tidak ada order yang kadaluarsa belakangan ini.")
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerHeartRoutes(r *Router) {
+	r.GET("/api/v1/me/hearts", RoleMiddleware("user")(GetMyHearts))
+	r.GET("/api/v1/users/me/hearts/history", AuthMiddleware(GetMyHeartHistory))
+	r.POST("/api/v1/admin/users/:id/hearts/restore", RoleMiddleware("admin")(RestoreUserHeart))
+	r.POST("/api/v1/admin/users/:id/hearts", RoleMiddleware("admin")(GrantUserHearts))
+}
+
+const (
+	// maxHearts is the balance a fresh account starts with and the cap for
+	// restorations.
+	maxHearts = 3
+	// heartRestoreEvery is how many paid orders earn one heart back.
+	heartRestoreEvery = 3
+)
+
+// HeartEvent is one recorded change to a user's hearts balance.
+type HeartEvent struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Delta     int       `json:"delta"`
+	Reason    string    `json:"reason"`
+	RefID     int64     `json:"ref_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// recordHeartEvent writes one row to the heart_events history. Failures are
+// logged rather than surfaced so the history never blocks the actual change.
+func recordHeartEvent(q dbtx, userID int64, delta int, reason string, refID int64) {
+	if _, err := q.Exec(
+		"INSERT INTO heart_events (user_id, delta, reason, ref_id, created_at) VALUES (?, ?, ?, ?, ?)",
+		userID, delta, reason, refID, nowUTC()); err != nil {
+		log.Printf("gagal mencatat heart event user %d: %v", userID, err)
+	}
+}
+
+// adjustHearts is the single path every hearts change goes through: it moves
+// the balance and writes the matching ledger entry in one place. The update
+// only applies when the new balance stays between 0 and maxHearts; it reports
+// whether anything changed.
+func adjustHearts(q dbtx, userID int64, delta int, reason string, refID int64) (bool, error) {
+	res, err := q.Exec(
+		"UPDATE users SET hearts = hearts + ? WHERE id = ? AND hearts + ? BETWEEN 0 AND ?",
+		delta, userID, delta, maxHearts)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return false, nil
+	}
+	recordHeartEvent(q, userID, delta, reason, refID)
+	if delta < 0 {
+		recordActivity(userID, "heart_lost", reason, refID)
+	}
+	return true, nil
+}
+
+// maybeRestoreHeart gives a heart back when the user completes a streak of
+// paid orders, so the balance can recover without admin help. Failures are
+// logged because restoration must never break payment confirmation.
+func maybeRestoreHeart(userID int64) {
+	var paid int
+	if err := db.QueryRow(
+		"SELECT COUNT(*) FROM orders WHERE user_id = ? AND status IN ('paid', 'delivered')",
+		userID).Scan(&paid); err != nil {
+		log.Printf("gagal cek streak order user %d: %v", userID, err)
+		return
+	}
+	if paid == 0 || paid%heartRestoreEvery != 0 {
+		return
+	}
+	changed, err := adjustHearts(db, userID, 1, "paid_orders_streak", 0)
+	if err != nil {
+		log.Printf("gagal restore heart user %d: %v", userID, err)
+		return
+	}
+	if changed {
+		createNotification(userID, "Heart dipulihkan",
+			"Kamu menyelesaikan beberapa order berturut-turut, 1 heart dikembalikan.")
+	}
+}
+
+// GetMyHearts returns the caller's current balance, the cap, and how long a
+// checkout made right now would hold its stock.
+func GetMyHearts(w http.ResponseWriter, r *http.Request) {
+	var hearts int
+	if err := db.QueryRow("SELECT hearts FROM users WHERE id = ?", currentUserID(r)).
+		Scan(&hearts); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	resp := map[string]any{"hearts": hearts, "max_hearts": maxHearts}
+	if duration, ok := reservationDuration(hearts); ok {
+		resp["reservation_hours"] = int(duration.Hours())
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// RestoreUserHeart lets an admin give a user one heart back, capped at the
+// normal maximum.
+func RestoreUserHeart(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	changed, err := adjustHearts(db, id, 1, "admin_grant", currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !changed {
+		respondError(w, http.StatusBadRequest, "user tidak ditemukan atau hearts sudah penuh")
+		return
+	}
+	createNotification(id, "Heart dipulihkan", "Admin mengembalikan 1 heart ke akun kamu.")
+	respondMessage(w, http.StatusOK, "heart berhasil dipulihkan")
+}
+
+// GrantUserHearts applies an arbitrary admin adjustment (positive or
+// negative); the change is rejected rather than clamped when it would push
+// the balance outside 0..maxHearts.
+func GrantUserHearts(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Delta  int    `json:"delta"`
+		Reason string `json:"reason"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Delta == 0 {
+		respondError(w, http.StatusBadRequest, "delta wajib diisi dan bukan nol")
+		return
+	}
+	if req.Reason == "" {
+		req.Reason = "admin_grant"
+	}
+	changed, err := adjustHearts(db, id, req.Delta, req.Reason, currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !changed {
+		respondError(w, http.StatusBadRequest, "user tidak ditemukan atau hasilnya di luar batas hearts")
+		return
+	}
+	if req.Delta > 0 {
+		createNotification(id, "Heart ditambahkan",
+			fmt.Sprintf("Admin menambahkan %d heart ke akun kamu.", req.Delta))
+	}
+	respondMessage(w, http.StatusOK, "hearts berhasil disesuaikan")
+}
+
+// GetMyHeartHistory lists the authenticated user's heart changes newest-first
+// so they can see why their reservation window changed, with optional keyset
+// pagination via ?limit= and ?cursor=.
+func GetMyHeartHistory(w http.ResponseWriter, r *http.Request) {
+	k := keysetParams(r)
+	query := "SELECT id, user_id, delta, reason, ref_id, created_at FROM heart_events WHERE user_id = ?"
+	args := []any{currentUserID(r)}
+	if k.Enabled {
+		if k.Cursor > 0 {
+			query += " AND id < ?"
+			args = append(args, k.Cursor)
+		}
+		query += " ORDER BY id DESC LIMIT ?"
+		args = append(args, k.Limit)
+	} else {
+		query += " ORDER BY id DESC"
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	events := []HeartEvent{}
+	for rows.Next() {
+		var e HeartEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Delta, &e.Reason, &e.RefID, &e.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		events = append(events, e)
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(events) > 0 {
+			lastID = events[len(events)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       events,
+			"next_cursor": nextCursor(lastID, len(events), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, events)
+}
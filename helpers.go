@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+func respondJSON(w http.ResponseWriter, status int, payload any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(payload)
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, map[string]string{"error": message})
+}
+
+func respondMessage(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, map[string]string{"message": message})
+}
+
+func decodeBody(r *http.Request, dst any) error {
+	return json.NewDecoder(r.Body).Decode(dst)
+}
+
+func paramInt(r *http.Request, name string) (int64, error) {
+	return strconv.ParseInt(Param(r, name), 10, 64)
+}
+
+func queryInt(r *http.Request, name string, fallback int64) int64 {
+	v, err := strconv.ParseInt(r.URL.Query().Get(name), 10, 64)
+	if err != nil {
+		return fallback
+	}
+	return v
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerImpersonationRoutes(r *Router) {
+	r.POST("/api/v1/admin/impersonate/:user_id", RoleMiddleware("admin")(ImpersonateUser))
+}
+
+// impersonationTTL is deliberately short: support sessions should end with
+// the ticket, not linger for a day.
+const impersonationTTL = 30 * time.Minute
+
+// ImpersonateUser issues a short-lived user-scoped token tagged with the
+// admin's id, so support can reproduce a customer's cart or order problem.
+// Every use lands in the audit log.
+func ImpersonateUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := paramInt(r, "user_id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "user_id tidak valid")
+		return
+	}
+	var username string
+	err = db.QueryRow("SELECT username FROM users WHERE id = ?", userID).Scan(&username)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "user tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	admin := currentClaims(r)
+	token, err := signClaims(Claims{
+		ID:             userID,
+		Role:           "user",
+		Exp:            nowUTC().Add(impersonationTTL).Unix(),
+		ImpersonatedBy: admin.ID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	recordAudit(r, "impersonate", "user", userID, nil,
+		map[string]any{"username": username, "ttl_minutes": int(impersonationTTL.Minutes())})
+	respondJSON(w, http.StatusOK, map[string]any{
+		"token":      token,
+		"role":       "user",
+		"expires_in": int(impersonationTTL.Seconds()),
+	})
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Claims is the JWT payload issued at login.
+type Claims struct {
+	ID   int64  `json:"id"`
+	Role string `json:"role"`
+	Exp  int64  `json:"exp"`
+	// ImpersonatedBy carries the admin's id when this is a support
+	// impersonation token, so downstream logs can tell the sessions apart.
+	ImpersonatedBy int64 `json:"imp,omitempty"`
+}
+
+var errInvalidToken = errors.New("token tidak valid")
+
+// jwtKey is one entry of the signing keyset. The first key signs new tokens;
+// the rest only verify, which is what lets JWT_SECRET rotate without logging
+// everyone out.
+type jwtKey struct {
+	ID     string
+	Secret string
+}
+
+var jwtKeys []jwtKey
+
+// initJWTKeys parses JWT_KEYS ("kid=secret,kid=secret", newest first) and
+// always appends the legacy JWT_SECRET as kid "v1" so outstanding tokens and
+// tokens without a kid keep verifying.
+func initJWTKeys() {
+	jwtKeys = jwtKeys[:0]
+	for _, pair := range strings.Split(config.JWTKeys, ",") {
+		if kid, secret, ok := strings.Cut(strings.TrimSpace(pair), "="); ok && kid != "" && secret != "" {
+			jwtKeys = append(jwtKeys, jwtKey{ID: kid, Secret: secret})
+		}
+	}
+	jwtKeys = append(jwtKeys, jwtKey{ID: "v1", Secret: config.JWTSecret})
+}
+
+func generateToken(id int64, role string) (string, error) {
+	ttl := time.Duration(config.AccessTokenTTLMinutes) * time.Minute
+	return signClaims(Claims{ID: id, Role: role, Exp: nowUTC().Add(ttl).Unix()})
+}
+
+func signClaims(claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signing := jwtKeys[0]
+	header := base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"alg":"HS256","typ":"JWT","kid":"` + signing.ID + `"}`))
+	body := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + signHS256With(body, signing.Secret), nil
+}
+
+func signHS256With(data, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(data))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signHS256 signs with the newest key; used for short-lived signed links.
+func signHS256(data string) string {
+	return signHS256With(data, jwtKeys[0].Secret)
+}
+
+// verifySignature checks the token signature against the key named by the
+// header's kid, or the whole keyset when the kid is absent or unknown.
+func verifySignature(headerB64, body, sig string) bool {
+	var header struct {
+		Kid string `json:"kid"`
+	}
+	if raw, err := base64.RawURLEncoding.DecodeString(headerB64); err == nil {
+		json.Unmarshal(raw, &header)
+	}
+	for _, key := range jwtKeys {
+		if header.Kid != "" && key.ID != header.Kid {
+			continue
+		}
+		if hmac.Equal([]byte(signHS256With(body, key.Secret)), []byte(sig)) {
+			return true
+		}
+	}
+	return false
+}
+
+func parseToken(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errInvalidToken
+	}
+	body := parts[0] + "." + parts[1]
+	if !verifySignature(parts[0], body, parts[2]) {
+		return nil, errInvalidToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, errInvalidToken
+	}
+	if nowUTC().Unix() > claims.Exp {
+		return nil, errors.New("token sudah kadaluarsa")
+	}
+	return &claims, nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerLoginHistoryRoutes(r *Router) {
+	r.GET("/api/v1/users/me/logins", AuthMiddleware(GetMyLogins))
+}
+
+// LoginEvent records one successful login with its device fingerprint.
+type LoginEvent struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Role      string    `json:"role"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// recordLogin stores the device fingerprint of a successful login and alerts
+// the user when the IP/user-agent pair has never been seen on their account.
+// Failures are logged; login itself must never break over history keeping.
+func recordLogin(userID int64, role string, r *http.Request) {
+	ip := clientIP(r)
+	agent := r.UserAgent()
+	var seen int
+	if err := db.QueryRow(
+		"SELECT COUNT(*) FROM login_events WHERE user_id = ? AND role = ? AND ip = ? AND user_agent = ?",
+		userID, role, ip, agent).Scan(&seen); err != nil {
+		log.Printf("gagal cek riwayat login user %d: %v", userID, err)
+		return
+	}
+	if _, err := db.Exec(
+		"INSERT INTO login_events (user_id, role, ip, user_agent, created_at) VALUES (?, ?, ?, ?, ?)",
+		userID, role, ip, agent, nowUTC()); err != nil {
+		log.Printf("gagal mencatat login user %d: %v", userID, err)
+		return
+	}
+	if role == "user" {
+		recordActivity(userID, "login", "Login dari IP "+ip, 0)
+	}
+	var total int
+	if err := db.QueryRow(
+		"SELECT COUNT(*) FROM login_events WHERE user_id = ? AND role = ?",
+		userID, role).Scan(&total); err != nil || total <= 1 {
+		// First login ever is not worth an alert.
+		return
+	}
+	if seen == 0 && role == "user" {
+		createNotification(userID, "Login dari perangkat baru",
+			"Akun kamu baru saja login dari perangkat atau lokasi yang belum dikenal (IP "+ip+"). "+
+				"Kalau ini bukan kamu, segera ganti password.")
+	}
+}
+
+// GetMyLogins lists the authenticated account's recent logins so users can
+// review where their account has been used.
+func GetMyLogins(w http.ResponseWriter, r *http.Request) {
+	claims := currentClaims(r)
+	rows, err := db.Query(
+		`SELECT id, user_id, role, ip, user_agent, created_at FROM login_events
+		 WHERE user_id = ? AND role = ? ORDER BY id DESC LIMIT 20`, claims.ID, claims.Role)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	events := []LoginEvent{}
+	for rows.Next() {
+		var e LoginEvent
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Role, &e.IP, &e.UserAgent, &e.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		events = append(events, e)
+	}
+	respondJSON(w, http.StatusOK, events)
+}
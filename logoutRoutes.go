@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func registerLogoutRoutes(r *Router) {
+	r.POST("/api/v1/logout", AuthMiddleware(Logout))
+}
+
+// Logout blacklists the presented access token until its natural expiry and
+// revokes the account's refresh tokens, so a stolen pair is dead immediately.
+func Logout(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" && config.CookieAuth {
+		token, _ = tokenFromCookie(r)
+	}
+	claims := currentClaims(r)
+	_, err := db.Exec(
+		"INSERT INTO revoked_tokens (token_hash, expires_at, created_at) VALUES (?, ?, ?)",
+		hashRefreshToken(token), time.Unix(claims.Exp, 0).UTC(), nowUTC())
+	if err != nil && !isDuplicateKeyError(err) {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Piggyback cleanup: rows past their token expiry can never match again.
+	if _, err := db.Exec("DELETE FROM revoked_tokens WHERE expires_at < ?", nowUTC()); err != nil {
+		log.Printf("gagal membersihkan revoked_tokens: %v", err)
+	}
+	if err := revokeRefreshTokens(claims.ID, claims.Role); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if config.CookieAuth {
+		clearAuthCookies(w)
+	}
+	respondMessage(w, http.StatusOK, "berhasil logout")
+}
+
+// isTokenRevoked reports whether an access token has been blacklisted by a
+// logout. Expired blacklist rows can't cause false positives because
+// parseToken already rejects expired tokens.
+func isTokenRevoked(token string) (bool, error) {
+	var n int
+	err := db.QueryRow("SELECT COUNT(*) FROM revoked_tokens WHERE token_hash = ?",
+		hashRefreshToken(token)).Scan(&n)
+	return n > 0, err
+}
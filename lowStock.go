@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func registerLowStockRoutes(r *Router) {
+	r.GET("/api/v1/admin/products/low-stock", RoleMiddleware("admin", "stocker")(GetLowStockProducts))
+}
+
+// checkLowStock runs after a stock decrement and alerts staff when the row
+// just crossed below its low_stock_threshold. Only the crossing triggers a
+// mail, so a product sitting under its threshold doesn't alert on every sale.
+// A threshold of 0 disables the check. Failures are logged, never surfaced.
+func checkLowStock(q dbtx, productID int64, variantID *int64, delta int) {
+	var (
+		label            string
+		stock, threshold int
+	)
+	var err error
+	if variantID != nil {
+		err = q.QueryRow(
+			`SELECT CONCAT(p.name, ' - ', v.name), v.stock, v.low_stock_threshold
+			 FROM product_variants v JOIN products p ON p.id = v.product_id WHERE v.id = ?`,
+			*variantID).Scan(&label, &stock, &threshold)
+	} else {
+		err = q.QueryRow("SELECT name, stock, low_stock_threshold FROM products WHERE id = ?",
+			productID).Scan(&label, &stock, &threshold)
+	}
+	if err != nil {
+		log.Printf("gagal cek low stock produk %d: %v", productID, err)
+		return
+	}
+	// delta is negative, so stock-delta is the level before this decrement.
+	if threshold <= 0 || stock >= threshold || stock-delta < threshold {
+		return
+	}
+	notifyLowStock(label, stock, threshold)
+}
+
+// notifyLowStock mails every admin and stocker that one item ran low.
+func notifyLowStock(label string, stock, threshold int) {
+	rows, err := db.Query(
+		"SELECT email FROM admins UNION SELECT email FROM employees WHERE role = 'stocker'")
+	if err != nil {
+		log.Printf("gagal ambil penerima alert low stock: %v", err)
+		return
+	}
+	defer rows.Close()
+	subject := "Stok menipis: " + label
+	body := fmt.Sprintf("Stok %s tinggal %d, di bawah batas minimum %d. Segera restock.",
+		label, stock, threshold)
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			log.Printf("gagal scan penerima alert low stock: %v", err)
+			return
+		}
+		enqueueMail(email, subject, body)
+	}
+}
+
+// lowStockItem is one row of the low-stock report.
+type lowStockItem struct {
+	ProductID         int64  `json:"product_id"`
+	VariantID         *int64 `json:"variant_id"`
+	Name              string `json:"name"`
+	Stock             int    `json:"stock"`
+	LowStockThreshold int    `json:"low_stock_threshold"`
+}
+
+// GetLowStockProducts lists every live product and variant currently below
+// its threshold, emptiest first, so staff can work the list top-down.
+func GetLowStockProducts(w http.ResponseWriter, r *http.Request) {
+	rows, err := readOnlyDB().Query(
+		`SELECT p.id, NULL, p.name, p.stock, p.low_stock_threshold
+		 FROM products p
+		 WHERE p.deleted_at IS NULL AND p.is_varians = 0
+		   AND p.low_stock_threshold > 0 AND p.stock < p.low_stock_threshold
+		 UNION ALL
+		 SELECT v.product_id, v.id, CONCAT(p.name, ' - ', v.name), v.stock, v.low_stock_threshold
+		 FROM product_variants v JOIN products p ON p.id = v.product_id
+		 WHERE v.deleted_at IS NULL AND p.deleted_at IS NULL
+		   AND v.low_stock_threshold > 0 AND v.stock < v.low_stock_threshold
+		 ORDER BY 4 ASC`)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	items := []lowStockItem{}
+	for rows.Next() {
+		var item lowStockItem
+		if err := rows.Scan(&item.ProductID, &item.VariantID, &item.Name,
+			&item.Stock, &item.LowStockThreshold); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		items = append(items, item)
+	}
+	respondJSON(w, http.StatusOK, items)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/smtp"
+	"time"
+)
+
+// Mailer sends one email. Implementations are chosen by config so dev and
+// staging run without real credentials.
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+// newMailer picks the implementation named by MAIL_PROVIDER.
+func newMailer() Mailer {
+	switch config.MailProvider {
+	case "smtp":
+		return smtpMailer{}
+	case "sendgrid":
+		return sendGridMailer{}
+	default:
+		return logMailer{}
+	}
+}
+
+// logMailer is the dev implementation: it only logs, so local runs never
+// email real customers.
+type logMailer struct{}
+
+func (logMailer) Send(to, subject, body string) error {
+	log.Printf("email (log-only) ke %s: %s", to, subject)
+	return nil
+}
+
+// smtpMailer sends through a plain SMTP relay.
+type smtpMailer struct{}
+
+func (smtpMailer) Send(to, subject, body string) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s",
+		config.MailFrom, to, subject, body)
+	addr := config.SMTPHost + ":" + config.SMTPPort
+	var auth smtp.Auth
+	if config.SMTPUser != "" {
+		auth = smtp.PlainAuth("", config.SMTPUser, config.SMTPPass, config.SMTPHost)
+	}
+	return smtp.SendMail(addr, auth, config.MailFrom, []string{to}, []byte(msg))
+}
+
+// sendGridMailer sends through the SendGrid v3 API.
+type sendGridMailer struct{}
+
+func (sendGridMailer) Send(to, subject, body string) error {
+	payload, err := json.Marshal(map[string]any{
+		"personalizations": []map[string]any{{"to": []map[string]string{{"email": to}}}},
+		"from":             map[string]string{"email": config.MailFrom},
+		"subject":          subject,
+		"content":          []map[string]string{{"type": "text/plain", "value": body}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost,
+		"https://api.sendgrid.com/v3/mail/send", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+config.SendGridAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid menjawab status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+type mailJob struct {
+	to, subject, body string
+}
+
+var (
+	mailer    Mailer
+	mailQueue = make(chan mailJob, 256)
+)
+
+// enqueueMail queues an email for background delivery; it drops (with a log
+// line) rather than blocks when the queue is full.
+func enqueueMail(to, subject, body string) {
+	select {
+	case mailQueue <- mailJob{to: to, subject: subject, body: body}:
+	default:
+		log.Printf("antrian email penuh, email ke %s tidak terkirim", to)
+	}
+}
+
+// StartMailWorker drains the mail queue in the background, retrying each
+// email up to three times with growing backoff, mirroring WithTx.
+func StartMailWorker() {
+	mailer = newMailer()
+	go func() {
+		for job := range mailQueue {
+			var err error
+			for attempt := 1; attempt <= 3; attempt++ {
+				if err = mailer.Send(job.to, job.subject, job.body); err == nil {
+					break
+				}
+				time.Sleep(time.Duration(attempt) * 2 * time.Second)
+			}
+			if err != nil {
+				log.Printf("gagal kirim email ke %s setelah 3 percobaan: %v", job.to, err)
+			}
+		}
+	}()
+}
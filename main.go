@@ -0,0 +1,94 @@
+package main
+
+import (
+	"log"
+	"net/http"
+)
+
+func main() {
+	LoadConfig()
+	if err := ConnectDatabase(); err != nil {
+		log.Fatalf("gagal konek database: %v", err)
+	}
+	initJWTKeys()
+	initRedis()
+	initWriteLimiter()
+	initSearchIndexer()
+	StartReservationExpiryWorker()
+	StartHeartRegenWorker()
+	StartProductPurgeWorker()
+	StartDiscountExpiryWorker()
+	StartRecommendationWorker()
+	StartSubscriptionWorker()
+	StartMailWorker()
+	initSMSSender()
+	initWhatsAppSender()
+	initQRISProvider()
+	StartFeedSyncWorker()
+
+	r := NewRouter()
+	registerAuthRoutes(r)
+	registerProductRoutes(r)
+	registerCategoryRoutes(r)
+	registerCartRoutes(r)
+	registerOrderRoutes(r)
+	registerReservationRoutes(r)
+	registerNotificationRoutes(r)
+	registerRestockRoutes(r)
+	registerWarehouseRoutes(r)
+	registerWishlistRoutes(r)
+	registerCouponRoutes(r)
+	registerBundleRoutes(r)
+	registerReturnRoutes(r)
+	registerTicketRoutes(r)
+	registerSearchRoutes(r)
+	registerSearchIndexRoutes(r)
+	registerRecommendationRoutes(r)
+	registerDigitalRoutes(r)
+	registerSubscriptionRoutes(r)
+	registerPosRoutes(r)
+	registerSupplierRoutes(r)
+	registerCheckoutRuleRoutes(r)
+	registerPromotionRoutes(r)
+	registerStockLedgerRoutes(r)
+	registerBulkRoutes(r)
+	registerHeartRoutes(r)
+	registerAdminUserRoutes(r)
+	registerLoginHistoryRoutes(r)
+	registerOtpRoutes(r)
+	registerFulfillmentRoutes(r)
+	registerDeliveryRoutes(r)
+	registerCodRoutes(r)
+	registerQrisRoutes(r)
+	registerPaymentWebhookRoutes(r)
+	registerCheckoutPreviewRoutes(r)
+	registerAdminOrderRoutes(r)
+	registerProductOptionRoutes(r)
+	registerHeartPolicyRoutes(r)
+	registerCampaignRoutes(r)
+	registerSupplierFeedRoutes(r)
+	registerSoftDeleteRoutes(r)
+	registerGdprRoutes(r)
+	registerRefreshTokenRoutes(r)
+	registerLogoutRoutes(r)
+	registerPasswordResetRoutes(r)
+	registerVerificationRoutes(r)
+	registerChangePasswordRoutes(r)
+	registerTwoFactorRoutes(r)
+	registerGoogleAuthRoutes(r)
+	registerSessionRoutes(r)
+	registerApiKeyRoutes(r)
+	registerAuditLogRoutes(r)
+	registerImpersonationRoutes(r)
+	registerRbacRoutes(r)
+	registerProfileRoutes(r)
+	registerAddressRoutes(r)
+	registerActivityRoutes(r)
+	registerPointsRoutes(r)
+	registerReferralRoutes(r)
+	registerProductHistoryRoutes(r)
+	registerLowStockRoutes(r)
+
+	log.Printf("server berjalan di port %s", config.Port)
+	log.Fatal(http.ListenAndServe(":"+config.Port, r))
+}
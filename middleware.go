@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type claimsKey struct{}
+
+// AuthMiddleware validates the bearer token and stores its claims on the request context.
+func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var token string
+		if header := r.Header.Get("Authorization"); strings.HasPrefix(header, "Bearer ") {
+			token = strings.TrimPrefix(header, "Bearer ")
+		} else if config.CookieAuth {
+			t, err := tokenFromCookie(r)
+			if err == errCSRFMismatch {
+				respondError(w, http.StatusForbidden, err.Error())
+				return
+			}
+			token = t
+		}
+		if token == "" {
+			respondError(w, http.StatusUnauthorized, "token tidak ditemukan")
+			return
+		}
+		claims, err := parseToken(token)
+		if err != nil {
+			respondError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if revoked, err := isTokenRevoked(token); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		} else if revoked {
+			respondError(w, http.StatusUnauthorized, "token sudah tidak berlaku")
+			return
+		}
+		if claims.Role == "user" {
+			if banned, err := isUserBanned(claims.ID); err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			} else if banned {
+				respondError(w, http.StatusForbidden, "akun kamu sedang ditangguhkan")
+				return
+			}
+		}
+		ctx := context.WithValue(r.Context(), claimsKey{}, claims)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// RoleMiddleware restricts a route to accounts holding one of the given roles.
+func RoleMiddleware(roles ...string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			claims := currentClaims(r)
+			for _, role := range roles {
+				if claims.Role == role {
+					next(w, r)
+					return
+				}
+			}
+			respondError(w, http.StatusForbidden, "akses ditolak untuk role ini")
+		})
+	}
+}
+
+func currentClaims(r *http.Request) *Claims {
+	claims, _ := r.Context().Value(claimsKey{}).(*Claims)
+	return claims
+}
+
+func currentUserID(r *http.Request) int64 {
+	return currentClaims(r).ID
+}
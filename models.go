@@ -0,0 +1,175 @@
+package main
+
+import "time"
+
+// User is a customer account.
+type User struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Phone     *string   `json:"phone"`
+	Hearts    int       `json:"hearts"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Category groups products.
+type Category struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Product is a catalog item; stock lives on the product unless is_varians is set.
+type Product struct {
+	ID                  int64      `json:"id"`
+	CategoryID          int64      `json:"category_id"`
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	Barcode             *string    `json:"barcode"`
+	SKU                 *string    `json:"sku"`
+	Price               float64    `json:"price"`
+	Stock               int        `json:"stock"`
+	LowStockThreshold   int        `json:"low_stock_threshold"`
+	IsVarians           bool       `json:"is_varians"`
+	IsDiscounted        bool       `json:"is_discounted"`
+	DiscountPrice       float64    `json:"discount_price"`
+	DiscountStartsAt    *time.Time `json:"discount_starts_at"`
+	DiscountEndsAt      *time.Time `json:"discount_ends_at"`
+	EffectivePrice      float64    `json:"effective_price"`
+	DiscountPercent     float64    `json:"discount_percent"`
+	IsService           bool       `json:"is_service"`
+	IsDigital           bool       `json:"is_digital"`
+	IsPreorder          bool       `json:"is_preorder"`
+	ExpectedAvailableAt *time.Time `json:"expected_available_at"`
+	WeightGrams         *int       `json:"weight_grams"`
+	LengthMM            *int       `json:"length_mm"`
+	WidthMM             *int       `json:"width_mm"`
+	HeightMM            *int       `json:"height_mm"`
+	Unit                string     `json:"unit"`
+	Status              string     `json:"status"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+	WishlistCount       int        `json:"wishlist_count"`
+}
+
+// ProductVariant is a sellable variation of a product with its own price and stock.
+type ProductVariant struct {
+	ID                int64      `json:"id"`
+	ProductID         int64      `json:"product_id"`
+	Name              string     `json:"name"`
+	SKU               *string    `json:"sku"`
+	Barcode           *string    `json:"barcode"`
+	Price             float64    `json:"price"`
+	Stock             int        `json:"stock"`
+	LowStockThreshold int        `json:"low_stock_threshold"`
+	IsDiscounted      bool       `json:"is_discounted"`
+	DiscountPrice     float64    `json:"discount_price"`
+	DiscountStartsAt  *time.Time `json:"discount_starts_at"`
+	DiscountEndsAt    *time.Time `json:"discount_ends_at"`
+	EffectivePrice    float64    `json:"effective_price"`
+	DiscountPercent   float64    `json:"discount_percent"`
+	// WeightGrams overrides the product weight when set; dimensions stay on
+	// the product.
+	WeightGrams *int `json:"weight_grams"`
+	Version     int  `json:"version"`
+}
+
+// ProductImage is a media item attached to a product: an image, or a video
+// with its thumbnail and duration.
+type ProductImage struct {
+	ID              int64   `json:"id"`
+	ProductID       int64   `json:"product_id"`
+	URL             string  `json:"url"`
+	MediaType       string  `json:"media_type"`
+	ThumbnailURL    *string `json:"thumbnail_url"`
+	DurationSeconds *int    `json:"duration_seconds"`
+}
+
+// CartItem is a row in a user's cart. Either product_id or bundle_id is set.
+type CartItem struct {
+	ID        int64   `json:"id"`
+	CartID    int64   `json:"cart_id"`
+	ProductID *int64  `json:"product_id"`
+	VariantID *int64  `json:"variant_id"`
+	BundleID  *int64  `json:"bundle_id"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+// Order is a checkout created from the cart, held by a reservation timer until paid.
+type Order struct {
+	ID              int64       `json:"id"`
+	UserID          int64       `json:"user_id"`
+	TotalPrice      float64     `json:"total_price"`
+	Status          string      `json:"status"`
+	PaymentMethod   string      `json:"payment_method"`
+	TimerExpiration *time.Time  `json:"timer_expiration"`
+	ExpiredAt       *time.Time  `json:"expired_at"`
+	CreatedAt       time.Time   `json:"created_at"`
+	Items           []OrderItem `json:"items,omitempty"`
+	// Fulfillments groups the items by how they ship; only populated on the
+	// detail endpoint.
+	Fulfillments []Fulfillment `json:"fulfillments,omitempty"`
+}
+
+// OrderItem is a purchased line inside an order. Either product_id or bundle_id is set.
+type OrderItem struct {
+	ID         int64   `json:"id"`
+	OrderID    int64   `json:"order_id"`
+	ProductID  *int64  `json:"product_id"`
+	VariantID  *int64  `json:"variant_id"`
+	BundleID   *int64  `json:"bundle_id"`
+	Quantity   int     `json:"quantity"`
+	Price      float64 `json:"price"`
+	IsPreorder bool    `json:"is_preorder"`
+	// Options carries the customization values copied from the cart line.
+	Options []ItemOption `json:"options,omitempty"`
+}
+
+// StockReservation holds stock for a user until it expires or is purchased.
+type StockReservation struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	OrderID   *int64    `json:"order_id"`
+	ProductID int64     `json:"product_id"`
+	VariantID *int64    `json:"variant_id"`
+	Quantity  int       `json:"quantity"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Notification is an in-app message for a user.
+type Notification struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	IsRead    bool      `json:"is_read"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestockRequest records a user asking for an out-of-stock item to be restocked.
+type RestockRequest struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	ProductID int64     `json:"product_id"`
+	VariantID *int64    `json:"variant_id"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Warehouse is a physical stock location.
+type Warehouse struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+// WarehouseStock is per-warehouse stock for a product or one of its variants.
+type WarehouseStock struct {
+	ID          int64  `json:"id"`
+	WarehouseID int64  `json:"warehouse_id"`
+	ProductID   int64  `json:"product_id"`
+	VariantID   *int64 `json:"variant_id"`
+	Stock       int    `json:"stock"`
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+)
+
+func registerNotificationRoutes(r *Router) {
+	r.GET("/api/v1/notifications", AuthMiddleware(GetMyNotifications))
+	r.GET("/api/v1/notifications/:id", AuthMiddleware(GetNotificationByID))
+	r.PATCH("/api/v1/notifications/:id/read", AuthMiddleware(MarkNotificationRead))
+	r.PATCH("/api/v1/users/me/notification-preferences", AuthMiddleware(UpdateNotificationPreferences))
+}
+
+// UpdateNotificationPreferences toggles the extra delivery channels for the
+// authenticated user; in-app notifications are always on.
+func UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		WAOptIn *bool `json:"wa_opt_in"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.WAOptIn == nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if _, err := db.Exec("UPDATE users SET wa_opt_in = ? WHERE id = ?",
+		*req.WAOptIn, currentUserID(r)); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "preferensi notifikasi disimpan")
+}
+
+// createNotification inserts an in-app notification for a user and fans it
+// out to WhatsApp when they opted in; failures are logged instead of
+// propagated so they never break the main flow.
+func createNotification(userID int64, title, message string) {
+	_, err := db.Exec(
+		"INSERT INTO notifications (user_id, title, message, is_read, created_at) VALUES (?, ?, ?, 0, ?)",
+		userID, title, message, nowUTC())
+	if err != nil {
+		log.Printf("gagal membuat notifikasi untuk user %d: %v", userID, err)
+	}
+	var (
+		phone sql.NullString
+		optIn bool
+	)
+	if err := db.QueryRow("SELECT phone, wa_opt_in FROM users WHERE id = ?", userID).
+		Scan(&phone, &optIn); err == nil && optIn && phone.Valid {
+		sendWhatsAppAsync(phone.String, title+": "+message)
+	}
+}
+
+const notificationColumns = "id, user_id, title, message, is_read, created_at"
+
+func scanNotification(row interface{ Scan(...any) error }) (Notification, error) {
+	var n Notification
+	err := row.Scan(&n.ID, &n.UserID, &n.Title, &n.Message, &n.IsRead, &n.CreatedAt)
+	return n, err
+}
+
+// GetMyNotifications lists the authenticated user's notifications, with
+// optional keyset pagination via ?limit= and ?cursor=.
+func GetMyNotifications(w http.ResponseWriter, r *http.Request) {
+	k := keysetParams(r)
+	query := "SELECT " + notificationColumns + " FROM notifications WHERE user_id = ?" + notDeleted("")
+	args := []any{currentUserID(r)}
+	if k.Enabled {
+		if k.Cursor > 0 {
+			query += " AND id < ?"
+			args = append(args, k.Cursor)
+		}
+		query += " ORDER BY id DESC LIMIT ?"
+		args = append(args, k.Limit)
+	} else {
+		query += " ORDER BY created_at DESC"
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	notifications := []Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		notifications = append(notifications, n)
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(notifications) > 0 {
+			lastID = notifications[len(notifications)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       notifications,
+			"next_cursor": nextCursor(lastID, len(notifications), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, notifications)
+}
+
+// GetNotificationByID returns one notification by id; only the recipient or
+// an admin may read it.
+func GetNotificationByID(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	n, err := scanNotification(db.QueryRow(
+		"SELECT "+notificationColumns+" FROM notifications WHERE id = ?"+notDeleted(""), id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "notifikasi tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if claims := currentClaims(r); claims.Role != "admin" && claims.ID != n.UserID {
+		respondError(w, http.StatusNotFound, "notifikasi tidak ditemukan")
+		return
+	}
+	respondJSON(w, http.StatusOK, n)
+}
+
+// MarkNotificationRead flags one of the user's notifications as read.
+func MarkNotificationRead(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("UPDATE notifications SET is_read = 1 WHERE id = ? AND user_id = ?",
+		id, currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "notifikasi tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "notifikasi ditandai sudah dibaca")
+}
@@ -0,0 +1,641 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func registerOrderRoutes(r *Router) {
+	r.POST("/api/v1/orders", AuthMiddleware(RateLimitMiddleware(CreateOrder)))
+	r.GET("/api/v1/orders", AuthMiddleware(GetMyOrders))
+	r.GET("/api/v1/orders/:id", OwnershipMiddleware("order")(GetOrderByID))
+	r.POST("/api/v1/orders/:id/pay", AuthMiddleware(ConfirmOrderPayment))
+	r.GET("/api/v1/orders/:id/countdown", OwnershipMiddleware("order")(GetOrderCountdown))
+	r.POST("/api/v1/admin/orders/:id/deliver", RoleMiddleware("admin")(MarkOrderDelivered))
+}
+
+// MarkOrderDelivered flips a paid order to delivered so returns can be filed.
+func MarkOrderDelivered(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var userID int64
+	var status string
+	err = db.QueryRow("SELECT user_id, status FROM orders WHERE id = ?", id).Scan(&userID, &status)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if status != "paid" {
+		respondError(w, http.StatusBadRequest, "hanya order yang sudah dibayar yang bisa ditandai delivered")
+		return
+	}
+	if _, err := db.Exec("UPDATE orders SET status = 'delivered' WHERE id = ?", id); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	recordAudit(r, "update", "order", id,
+		map[string]string{"status": status}, map[string]string{"status": "delivered"})
+	createNotification(userID, "Order sampai", "Order kamu sudah sampai tujuan. Selamat menikmati!")
+	respondMessage(w, http.StatusOK, "order ditandai delivered")
+}
+
+// CreateOrder turns the user's cart into a waitToBuy order, reserving stock
+// for a duration that depends on how many hearts the user still has.
+func CreateOrder(w http.ResponseWriter, r *http.Request) {
+	if !requireVerifiedUser(w, r) {
+		return
+	}
+	userID := currentUserID(r)
+	var req struct {
+		CouponCode    string `json:"coupon_code"`
+		PaymentMethod string `json:"payment_method"`
+		DeliveryArea  string `json:"delivery_area"`
+		AddressID     int64  `json:"address_id"`
+		RedeemPoints  int    `json:"redeem_points"`
+	}
+	decodeBody(r, &req)
+	if req.PaymentMethod == "" {
+		req.PaymentMethod = "transfer"
+	}
+	if req.PaymentMethod != "transfer" && req.PaymentMethod != "cod" {
+		respondError(w, http.StatusBadRequest, "payment_method harus transfer atau cod")
+		return
+	}
+	// Fall back to the default address; an explicit address_id must be the
+	// caller's own. Orders without any saved address ship with address NULL,
+	// as before the address book existed.
+	addressID := sql.NullInt64{Int64: req.AddressID, Valid: req.AddressID > 0}
+	if addressID.Valid {
+		var owned int64
+		err := db.QueryRow("SELECT id FROM addresses WHERE id = ? AND user_id = ?",
+			req.AddressID, userID).Scan(&owned)
+		if err == sql.ErrNoRows {
+			respondError(w, http.StatusNotFound, "alamat tidak ditemukan")
+			return
+		} else if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else {
+		err := db.QueryRow("SELECT id FROM addresses WHERE user_id = ? AND is_default = 1",
+			userID).Scan(&addressID.Int64)
+		if err == nil {
+			addressID.Valid = true
+		} else if err != sql.ErrNoRows {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	release, locked, err := acquireCheckoutLock(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !locked {
+		respondError(w, http.StatusConflict, "checkout kamu sedang diproses, tunggu sebentar")
+		return
+	}
+	defer release()
+	cs, ok := computeCheckout(w, r, req.CouponCode)
+	if !ok {
+		return
+	}
+
+	var hearts int
+	if err := db.QueryRow("SELECT hearts FROM users WHERE id = ?", userID).Scan(&hearts); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	duration, ok := reservationDuration(hearts)
+	if !ok {
+		respondError(w, http.StatusForbidden, "hearts habis, tidak bisa membuat order")
+		return
+	}
+
+	// Points come off the total before COD limits and the stored order price.
+	if req.RedeemPoints < 0 {
+		respondError(w, http.StatusBadRequest, "redeem_points tidak valid")
+		return
+	}
+	var pointsUsed int
+	if req.RedeemPoints > 0 {
+		discount := pointsDiscount(req.RedeemPoints)
+		if discount > cs.Total {
+			respondError(w, http.StatusBadRequest, "points yang ditukar melebihi total order")
+			return
+		}
+		pointsUsed = req.RedeemPoints
+		cs.Total -= discount
+	}
+
+	if req.PaymentMethod == "cod" {
+		if msg := codEligibilityError(cs.Total, req.DeliveryArea); msg != "" {
+			respondError(w, http.StatusBadRequest, msg)
+			return
+		}
+	}
+
+	expiration := nowUTC().Add(duration)
+	var orderID int64
+	err = WithTx(func(tx *sql.Tx) error {
+		var err error
+		if orderID, err = writeOrder(tx, userID, cs, req.PaymentMethod, addressID, expiration); err != nil {
+			return err
+		}
+		if pointsUsed > 0 {
+			changed, err := adjustPoints(tx, userID, -pointsUsed, "redeem", orderID)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				return errInsufficientPoints
+			}
+		}
+		if _, err := tx.Exec(
+			`DELETE cio FROM cart_item_options cio
+			 JOIN cart_items ci ON ci.id = cio.cart_item_id WHERE ci.cart_id = ?`, cs.CartID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM cart_items WHERE cart_id = ?", cs.CartID); err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE carts SET total_price = 0 WHERE id = ?", cs.CartID)
+		return err
+	})
+	if err == errInsufficientPoints {
+		respondError(w, http.StatusBadRequest, "points tidak cukup untuk ditukar")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	recordActivity(userID, "order_created",
+		fmt.Sprintf("Order dibuat dengan total %.2f", cs.Total), orderID)
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"id":               orderID,
+		"total_price":      cs.Total,
+		"discount":         cs.CouponDiscount,
+		"points_used":      pointsUsed,
+		"status":           "waitToBuy",
+		"timer_expiration": expiration,
+	})
+}
+
+// writeOrder inserts an order with its coupon usage, promotions,
+// fulfillments, items, and stock reservations inside the caller's
+// transaction. Customer checkout and admin phone orders both go through
+// here.
+func writeOrder(tx *sql.Tx, userID int64, cs *checkoutSummary, paymentMethod string, addressID sql.NullInt64, expiration time.Time) (int64, error) {
+	res, err := tx.Exec(
+		"INSERT INTO orders (user_id, total_price, status, payment_method, address_id, timer_expiration, created_at) VALUES (?, ?, 'waitToBuy', ?, ?, ?, ?)",
+		userID, cs.Total, paymentMethod, addressID, expiration, nowUTC())
+	if err != nil {
+		return 0, err
+	}
+	orderID, _ := res.LastInsertId()
+	if cs.Coupon != nil {
+		if err := recordCouponUsage(tx, cs.Coupon.ID, userID, orderID); err != nil {
+			return 0, err
+		}
+	}
+	if err := recordOrderPromotions(tx, orderID, cs.Promotions); err != nil {
+		return 0, err
+	}
+
+	// One fulfillment per fulfillment type present, so physical shipping
+	// and digital delivery track their own status.
+	var hasPhysical, hasDigital bool
+	for _, l := range cs.Lines {
+		if l.Digital {
+			hasDigital = true
+		} else {
+			hasPhysical = true
+		}
+	}
+	for _, a := range cs.Promotions {
+		if a.GiftProductID != nil {
+			hasPhysical = true
+		}
+	}
+	var physicalFID, digitalFID int64
+	if hasPhysical {
+		if physicalFID, err = createFulfillment(tx, orderID, "physical"); err != nil {
+			return 0, err
+		}
+	}
+	if hasDigital {
+		if digitalFID, err = createFulfillment(tx, orderID, "digital"); err != nil {
+			return 0, err
+		}
+	}
+	fulfillmentFor := func(digital bool) int64 {
+		if digital {
+			return digitalFID
+		}
+		return physicalFID
+	}
+
+	for _, a := range cs.Promotions {
+		if a.GiftProductID == nil {
+			continue
+		}
+		// Gift lines ride along for free and skip stock holds.
+		if _, err := tx.Exec(
+			"INSERT INTO order_items (order_id, fulfillment_id, product_id, quantity, price, is_preorder) VALUES (?, ?, ?, 1, 0, 0)",
+			orderID, fulfillmentFor(false), *a.GiftProductID); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, l := range cs.Lines {
+		itemRes, err := tx.Exec(
+			"INSERT INTO order_items (order_id, fulfillment_id, product_id, variant_id, bundle_id, quantity, price, is_preorder) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			orderID, fulfillmentFor(l.Digital), l.ProductID, l.VariantID, l.BundleID, l.Quantity, l.Price, l.Preorder)
+		if err != nil {
+			return 0, err
+		}
+		if l.CartItemID != 0 {
+			orderItemID, _ := itemRes.LastInsertId()
+			if err := copyCartItemOptions(tx, l.CartItemID, orderItemID); err != nil {
+				return 0, err
+			}
+		}
+		if l.Preorder {
+			// Pre-order lines skip the stock decrement and reservation; they are
+			// fulfilled when the stock arrives.
+			continue
+		}
+		if l.Digital {
+			// Digital lines have no physical stock to hold.
+			continue
+		}
+		if l.BundleID.Valid {
+			if err := reserveBundleComponents(tx, userID, orderID, l.BundleID.Int64, l.Quantity, expiration); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		var vID *int64
+		if l.VariantID.Valid {
+			vID = &l.VariantID.Int64
+		}
+		if err := stockSvc.Reserve(tx, l.ProductID.Int64, vID, l.Quantity); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(
+			`INSERT INTO stock_reservations (user_id, order_id, product_id, variant_id, quantity, status, expires_at, created_at)
+			 VALUES (?, ?, ?, ?, ?, 'active', ?, ?)`,
+			userID, orderID, l.ProductID, l.VariantID, l.Quantity, expiration, nowUTC()); err != nil {
+			return 0, err
+		}
+	}
+	return orderID, nil
+}
+
+// GetOrderCountdown returns the seconds left on an order's reservation
+// timer, computed server-side so client clock skew cannot distort it.
+func GetOrderCountdown(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var (
+		status string
+		timer  sql.NullTime
+	)
+	err = db.QueryRow("SELECT status, timer_expiration FROM orders WHERE id = ?",
+		id).Scan(&status, &timer)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var remaining int64
+	if status == "waitToBuy" && timer.Valid {
+		if left := timer.Time.Sub(nowUTC()); left > 0 {
+			remaining = int64(left.Seconds())
+		}
+	}
+	resp := map[string]any{
+		"status":            status,
+		"remaining_seconds": remaining,
+	}
+	if timer.Valid {
+		resp["timer_expiration"] = timer.Time
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+const orderColumns = "id, user_id, total_price, status, payment_method, timer_expiration, expired_at, created_at"
+
+func scanOrder(row interface{ Scan(...any) error }) (Order, error) {
+	var (
+		o         Order
+		timer     sql.NullTime
+		expiredAt sql.NullTime
+	)
+	err := row.Scan(&o.ID, &o.UserID, &o.TotalPrice, &o.Status, &o.PaymentMethod, &timer, &expiredAt, &o.CreatedAt)
+	if timer.Valid {
+		o.TimerExpiration = &timer.Time
+	}
+	if expiredAt.Valid {
+		o.ExpiredAt = &expiredAt.Time
+	}
+	return o, err
+}
+
+func fetchOrderItems(orderID int64) ([]OrderItem, error) {
+	rows, err := db.Query(
+		"SELECT id, order_id, product_id, variant_id, bundle_id, quantity, price, is_preorder FROM order_items WHERE order_id = ?",
+		orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OrderItem{}
+	for rows.Next() {
+		var (
+			it        OrderItem
+			productID sql.NullInt64
+			variantID sql.NullInt64
+			bundleID  sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.OrderID, &productID, &variantID, &bundleID, &it.Quantity, &it.Price, &it.IsPreorder); err != nil {
+			return nil, err
+		}
+		if productID.Valid {
+			it.ProductID = &productID.Int64
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		if bundleID.Valid {
+			it.BundleID = &bundleID.Int64
+		}
+		items = append(items, it)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		options, err := fetchItemOptions("order_item_options", items[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		items[i].Options = options
+	}
+	return items, nil
+}
+
+// notifyPreorderArrival tells everyone with an open pre-order line on the
+// product that its stock has arrived and fulfillment will start.
+func notifyPreorderArrival(productID int64) {
+	rows, err := db.Query(
+		`SELECT DISTINCT o.user_id FROM order_items oi
+		 JOIN orders o ON o.id = oi.order_id
+		 WHERE oi.product_id = ? AND oi.is_preorder = 1 AND o.status IN ('waitToBuy', 'paid')`,
+		productID)
+	if err != nil {
+		log.Printf("gagal ambil pre-order produk %d: %v", productID, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			log.Printf("gagal scan pre-order produk %d: %v", productID, err)
+			return
+		}
+		createNotification(userID, "Stock pre-order tiba",
+			"Stock untuk produk pre-order kamu sudah tiba dan akan segera diproses.")
+	}
+}
+
+// reserveBundleComponents decrements stock and writes a reservation for every
+// component of a bundle line, using the caller's transaction.
+func reserveBundleComponents(q dbtx, userID, orderID, bundleID int64, sets int, expiration time.Time) error {
+	items, err := fetchBundleItems(bundleID)
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		qty := it.Quantity * sets
+		if err := stockSvc.Reserve(q, it.ProductID, it.VariantID, qty); err != nil {
+			return err
+		}
+		if _, err := q.Exec(
+			`INSERT INTO stock_reservations (user_id, order_id, product_id, variant_id, quantity, status, expires_at, created_at)
+			 VALUES (?, ?, ?, ?, ?, 'active', ?, ?)`,
+			userID, orderID, it.ProductID, it.VariantID, qty, expiration, nowUTC()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchOrderItemsBatch loads the items of many orders in one query, keyed by
+// order id, so listings avoid a per-order round trip.
+func fetchOrderItemsBatch(orderIDs []int64) (map[int64][]OrderItem, error) {
+	byOrder := map[int64][]OrderItem{}
+	if len(orderIDs) == 0 {
+		return byOrder, nil
+	}
+	placeholders := strings.Repeat("?, ", len(orderIDs)-1) + "?"
+	args := make([]any, len(orderIDs))
+	for i, id := range orderIDs {
+		args[i] = id
+	}
+	rows, err := db.Query(
+		"SELECT id, order_id, product_id, variant_id, bundle_id, quantity, price, is_preorder FROM order_items WHERE order_id IN ("+placeholders+")",
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			it        OrderItem
+			productID sql.NullInt64
+			variantID sql.NullInt64
+			bundleID  sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.OrderID, &productID, &variantID, &bundleID, &it.Quantity, &it.Price, &it.IsPreorder); err != nil {
+			return nil, err
+		}
+		if productID.Valid {
+			it.ProductID = &productID.Int64
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		if bundleID.Valid {
+			it.BundleID = &bundleID.Int64
+		}
+		byOrder[it.OrderID] = append(byOrder[it.OrderID], it)
+	}
+	return byOrder, rows.Err()
+}
+
+// GetMyOrders lists the authenticated user's orders with their items, with
+// optional keyset pagination via ?limit= and ?cursor=.
+func GetMyOrders(w http.ResponseWriter, r *http.Request) {
+	k := keysetParams(r)
+	query := "SELECT " + orderColumns + " FROM orders WHERE user_id = ?"
+	args := []any{currentUserID(r)}
+	if k.Enabled {
+		if k.Cursor > 0 {
+			query += " AND id < ?"
+			args = append(args, k.Cursor)
+		}
+		query += " ORDER BY id DESC LIMIT ?"
+		args = append(args, k.Limit)
+	} else {
+		query += " ORDER BY created_at DESC"
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	orders := []Order{}
+	orderIDs := []int64{}
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		orders = append(orders, o)
+		orderIDs = append(orderIDs, o.ID)
+	}
+	itemsByOrder, err := fetchOrderItemsBatch(orderIDs)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for i := range orders {
+		items := itemsByOrder[orders[i].ID]
+		if items == nil {
+			items = []OrderItem{}
+		}
+		orders[i].Items = items
+	}
+	fields := fieldSet(r)
+	if k.Enabled {
+		var lastID int64
+		if len(orders) > 0 {
+			lastID = orders[len(orders)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       pruneFields(orders, fields),
+			"next_cursor": nextCursor(lastID, len(orders), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, pruneFields(orders, fields))
+}
+
+// GetOrderByID returns one of the authenticated user's orders with items.
+func GetOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	o, err := scanOrder(db.QueryRow(
+		"SELECT "+orderColumns+" FROM orders WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	o.Items, err = fetchOrderItems(o.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	o.Fulfillments, err = fetchOrderFulfillments(o.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, pruneFields(o, fieldSet(r)))
+}
+
+// ConfirmOrderPayment marks a waitToBuy order as paid and finalizes its reservations.
+func ConfirmOrderPayment(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var status string
+	err = db.QueryRow("SELECT status FROM orders WHERE id = ? AND user_id = ?",
+		id, currentUserID(r)).Scan(&status)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if status != "waitToBuy" {
+		respondError(w, http.StatusBadRequest, "order tidak dalam status waitToBuy")
+		return
+	}
+	if err := finalizeOrderPayment(id); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "pembayaran berhasil dikonfirmasi")
+}
+
+// finalizeOrderPayment marks an order paid, locks in its reservations, hands
+// out license keys, and notifies the customer. Shared by online payment
+// confirmation and COD settlement.
+func finalizeOrderPayment(id int64) error {
+	var userID int64
+	if err := db.QueryRow("SELECT user_id FROM orders WHERE id = ?", id).Scan(&userID); err != nil {
+		return err
+	}
+	if _, err := db.Exec("UPDATE orders SET status = 'paid' WHERE id = ?", id); err != nil {
+		return err
+	}
+	if _, err := db.Exec("UPDATE stock_reservations SET status = 'purchased', status_changed_at = ? WHERE order_id = ?", nowUTC(), id); err != nil {
+		return err
+	}
+	assignLicenseKeys(id)
+	maybeRestoreHeart(userID)
+	awardOrderPoints(userID, id)
+	maybeRewardReferral(userID)
+	var (
+		email string
+		phone sql.NullString
+	)
+	if err := db.QueryRow("SELECT email, phone FROM users WHERE id = ?", userID).
+		Scan(&email, &phone); err == nil {
+		enqueueMail(email, "Pembayaran diterima",
+			fmt.Sprintf("Pembayaran untuk order #%d sudah kami terima. Terima kasih!", id))
+		if phone.Valid {
+			sendSMSAsync(phone.String,
+				fmt.Sprintf("Pembayaran order #%d diterima. Terima kasih!", id))
+		}
+	}
+	return nil
+}
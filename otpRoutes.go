@@ -0,0 +1,95 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+)
+
+func registerOtpRoutes(r *Router) {
+	r.POST("/api/v1/otp/request", RequestOtp)
+	r.POST("/api/v1/otp/login", LoginWithOtp)
+}
+
+const otpTTL = 5 * time.Minute
+
+// generateOtpCode returns a random 6-digit code.
+func generateOtpCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// RequestOtp texts a login code to a registered phone number. The response is
+// the same whether or not the number exists, so it cannot be used to probe
+// which phones are registered.
+func RequestOtp(w http.ResponseWriter, r *http.Request) {
+	if !requireAuthAttempt(w, r) {
+		return
+	}
+	var req struct {
+		Phone string `json:"phone"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Phone == "" {
+		respondError(w, http.StatusBadRequest, "phone wajib diisi")
+		return
+	}
+	var userID int64
+	err := db.QueryRow("SELECT id FROM users WHERE phone = ?", req.Phone).Scan(&userID)
+	if err == nil {
+		code, genErr := generateOtpCode()
+		if genErr != nil {
+			respondError(w, http.StatusInternalServerError, genErr.Error())
+			return
+		}
+		if _, insErr := db.Exec(
+			"INSERT INTO otp_codes (phone, code, expires_at, used, created_at) VALUES (?, ?, ?, 0, ?)",
+			req.Phone, code, nowUTC().Add(otpTTL), nowUTC()); insErr != nil {
+			respondError(w, http.StatusInternalServerError, insErr.Error())
+			return
+		}
+		sendSMSAsync(req.Phone, "Kode login kamu: "+code+". Berlaku 5 menit.")
+	} else if err != sql.ErrNoRows {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "jika nomor terdaftar, kode OTP sudah dikirim")
+}
+
+// LoginWithOtp exchanges a valid OTP code for a normal user token.
+func LoginWithOtp(w http.ResponseWriter, r *http.Request) {
+	if !requireAuthAttempt(w, r) {
+		return
+	}
+	var req struct {
+		Phone string `json:"phone"`
+		Code  string `json:"code"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Phone == "" || req.Code == "" {
+		respondError(w, http.StatusBadRequest, "phone dan code wajib diisi")
+		return
+	}
+	res, err := db.Exec(
+		"UPDATE otp_codes SET used = 1 WHERE phone = ? AND code = ? AND used = 0 AND expires_at > ?",
+		req.Phone, req.Code, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusUnauthorized, "kode OTP salah atau kadaluarsa")
+		return
+	}
+	var userID int64
+	if err := db.QueryRow("SELECT id FROM users WHERE phone = ?", req.Phone).Scan(&userID); err != nil {
+		respondError(w, http.StatusUnauthorized, "kode OTP salah atau kadaluarsa")
+		return
+	}
+	recordLogin(userID, "user", r)
+	respondWithToken(w, r, userID, "user", false)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+// ownerQueries maps an entity name to the query resolving who owns the row
+// behind the :id parameter.
+var ownerQueries = map[string]string{
+	"order":       "SELECT user_id FROM orders WHERE id = ?",
+	"cart_item":   "SELECT c.user_id FROM cart_items ci JOIN carts c ON c.id = ci.cart_id WHERE ci.id = ?",
+	"reservation": "SELECT user_id FROM stock_reservations WHERE id = ?",
+}
+
+// OwnershipMiddleware verifies that the :id resource belongs to the JWT user
+// before the handler runs; admins pass unconditionally. Foreign rows get the
+// same 404 as missing ones so ids can't be probed.
+func OwnershipMiddleware(entity string) func(http.HandlerFunc) http.HandlerFunc {
+	query := ownerQueries[entity]
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			id, err := paramInt(r, "id")
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "id tidak valid")
+				return
+			}
+			var ownerID int64
+			err = db.QueryRow(query, id).Scan(&ownerID)
+			if err == sql.ErrNoRows {
+				respondError(w, http.StatusNotFound, entity+" tidak ditemukan")
+				return
+			} else if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if claims := currentClaims(r); claims.Role != "admin" && claims.ID != ownerID {
+				respondError(w, http.StatusNotFound, entity+" tidak ditemukan")
+				return
+			}
+			next(w, r)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// keyset holds cursor pagination parameters read from the query string.
+// Enabled is false when the client did not ask for pagination, so listings
+// keep their original return-everything behavior.
+type keyset struct {
+	Enabled bool
+	Cursor  int64
+	Limit   int64
+}
+
+// maxPageSize caps ?limit= so one request can never drag a whole table.
+const maxPageSize = 100
+
+// keysetParams reads ?cursor= (last seen id) and ?limit= from the request.
+func keysetParams(r *http.Request) keyset {
+	limit := queryInt(r, "limit", 0)
+	if limit <= 0 {
+		return keyset{}
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return keyset{
+		Enabled: true,
+		Cursor:  queryInt(r, "cursor", 0),
+		Limit:   limit,
+	}
+}
+
+// nextCursor returns the cursor for the following page: the last id of this
+// page when it was full, or 0 when there is nothing more to fetch.
+func nextCursor(lastID int64, fetched int, k keyset) int64 {
+	if int64(fetched) < k.Limit {
+		return 0
+	}
+	return lastID
+}
+
+// page holds offset pagination read from ?page=/?limit= plus an ORDER BY
+// built from ?sort=. Enabled is false when the client did not send ?page=,
+// so endpoints fall back to keyset or return-everything behavior.
+type page struct {
+	Enabled bool
+	Number  int64
+	Limit   int64
+	OrderBy string
+}
+
+// pageParams reads ?page=, ?limit=, and ?sort= from the request. sortable
+// maps allowed sort keys to their SQL columns; ?sort= takes comma-separated
+// keys with a leading '-' for descending (e.g. ?sort=price,-created_at).
+// An unknown sort key is an error so typos fail loudly instead of silently
+// reordering nothing.
+func pageParams(r *http.Request, sortable map[string]string, defaultOrder string) (page, error) {
+	orderBy, err := sortClause(r.URL.Query().Get("sort"), sortable, defaultOrder)
+	if err != nil {
+		return page{}, err
+	}
+	number := queryInt(r, "page", 0)
+	if number <= 0 {
+		return page{}, nil
+	}
+	limit := queryInt(r, "limit", 20)
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	return page{Enabled: true, Number: number, Limit: limit, OrderBy: orderBy}, nil
+}
+
+func sortClause(sort string, sortable map[string]string, defaultOrder string) (string, error) {
+	if sort == "" {
+		return defaultOrder, nil
+	}
+	var parts []string
+	for _, key := range strings.Split(sort, ",") {
+		key = strings.TrimSpace(key)
+		dir := " ASC"
+		if strings.HasPrefix(key, "-") {
+			key = key[1:]
+			dir = " DESC"
+		}
+		column, ok := sortable[key]
+		if !ok {
+			return "", fmt.Errorf("sort %q tidak dikenal", key)
+		}
+		parts = append(parts, column+dir)
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// offset returns the OFFSET matching the page number.
+func (p page) offset() int64 {
+	return (p.Number - 1) * p.Limit
+}
+
+// pageEnvelope wraps one page of results in the standard pagination envelope
+// with total counts, shared by every ?page=-style listing.
+func pageEnvelope(items any, p page, total int64) map[string]any {
+	return map[string]any{
+		"items":       items,
+		"page":        p.Number,
+		"limit":       p.Limit,
+		"total":       total,
+		"total_pages": (total + p.Limit - 1) / p.Limit,
+	}
+}
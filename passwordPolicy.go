@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// validatePassword checks a candidate password against the configured policy
+// and returns every violation, so clients can show all problems at once
+// instead of one per submit.
+func validatePassword(pw string) []string {
+	var violations []string
+	if len(pw) < config.PasswordMinLen {
+		violations = append(violations,
+			fmt.Sprintf("password minimal %d karakter", config.PasswordMinLen))
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range pw {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		violations = append(violations, "password harus mengandung huruf dan angka")
+	}
+	if isCommonPassword(pw) {
+		violations = append(violations, "password terlalu umum, pilih yang lebih unik")
+	}
+	if len(violations) == 0 && isBreachedPassword(pw) {
+		violations = append(violations,
+			"password ini pernah bocor di kebocoran data, pilih yang lain")
+	}
+	return violations
+}
+
+// commonPasswords are rejected outright; PASSWORD_DENYLIST appends more.
+var commonPasswords = []string{
+	"password", "password1", "12345678", "123456789", "qwerty123",
+	"abc12345", "indonesia1", "admin123", "rahasia1",
+}
+
+// isCommonPassword checks the built-in denylist plus the configured extras,
+// case-insensitively.
+func isCommonPassword(pw string) bool {
+	lower := strings.ToLower(pw)
+	for _, banned := range commonPasswords {
+		if lower == banned {
+			return true
+		}
+	}
+	for _, banned := range strings.Split(config.PasswordDenylist, ",") {
+		if banned = strings.TrimSpace(strings.ToLower(banned)); banned != "" && lower == banned {
+			return true
+		}
+	}
+	return false
+}
+
+// requireValidPassword writes a structured 400 and returns false when the
+// password violates the policy.
+func requireValidPassword(w http.ResponseWriter, pw string) bool {
+	if violations := validatePassword(pw); len(violations) > 0 {
+		respondJSON(w, http.StatusBadRequest, map[string]any{"errors": violations})
+		return false
+	}
+	return true
+}
+
+// isBreachedPassword asks the configured range API (k-anonymity: only the
+// first 5 hex chars of the SHA-1 leave the server) whether the password has
+// appeared in a known breach. Disabled when no URL is configured, and fails
+// open on network errors so an outage never blocks signups.
+func isBreachedPassword(pw string) bool {
+	if config.PwnedRangeURL == "" {
+		return false
+	}
+	sum := fmt.Sprintf("%X", sha1.Sum([]byte(pw)))
+	prefix, suffix := sum[:5], sum[5:]
+	client := &http.Client{Timeout: 3 * time.Second}
+	resp, err := client.Get(strings.TrimRight(config.PwnedRangeURL, "/") + "/" + prefix)
+	if err != nil {
+		log.Printf("gagal cek breached password: %v", err)
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(string(body), "\n") {
+		if candidate, _, ok := strings.Cut(strings.TrimSpace(line), ":"); ok && candidate == suffix {
+			return true
+		}
+	}
+	return false
+}
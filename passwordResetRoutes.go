@@ -0,0 +1,141 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func registerPasswordResetRoutes(r *Router) {
+	r.POST("/api/v1/auth/forgot-password", ForgotPassword)
+	r.POST("/api/v1/auth/reset-password", ResetPassword)
+}
+
+const resetTokenTTL = time.Hour
+
+// accountTable maps a role to the table its credentials live in.
+func accountTable(role string) (string, bool) {
+	switch role {
+	case "user":
+		return "users", true
+	case "admin":
+		return "admins", true
+	case "stocker", "cashier", "driver", "employee":
+		return "employees", true
+	}
+	return "", false
+}
+
+// ForgotPassword creates a single-use reset token and mails it to the account.
+// The response is the same whether or not the email exists, so the endpoint
+// can't be used to probe which addresses are registered.
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	if !requireAuthAttempt(w, r) {
+		return
+	}
+	var req struct {
+		Email string `json:"email"`
+		Role  string `json:"role"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Email == "" {
+		respondError(w, http.StatusBadRequest, "email wajib diisi")
+		return
+	}
+	if req.Role == "" {
+		req.Role = "user"
+	}
+	table, ok := accountTable(req.Role)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "role tidak dikenal")
+		return
+	}
+	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	neutral := "kalau email terdaftar, link reset sudah dikirim"
+	var accountID int64
+	err := db.QueryRow("SELECT id FROM "+table+" WHERE email = ?", req.Email).Scan(&accountID)
+	if err == sql.ErrNoRows {
+		respondMessage(w, http.StatusOK, neutral)
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	token := hex.EncodeToString(raw)
+	_, err = db.Exec(
+		"INSERT INTO password_resets (account_id, role, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		accountID, req.Role, hashRefreshToken(token), nowUTC().Add(resetTokenTTL), nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	enqueueMail(req.Email, "Reset password",
+		fmt.Sprintf("Gunakan token berikut untuk reset password (berlaku 1 jam): %s", token))
+	respondMessage(w, http.StatusOK, neutral)
+}
+
+// ResetPassword consumes a reset token and sets the new password. The token
+// row is marked used inside the same transaction as the password update, so
+// one token can never apply twice.
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	if !requireAuthAttempt(w, r) {
+		return
+	}
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Token == "" {
+		respondError(w, http.StatusBadRequest, "token wajib diisi")
+		return
+	}
+	if !requireValidPassword(w, req.Password) {
+		return
+	}
+	hashed, err := hashPassword(req.Password)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var (
+		accountID int64
+		role      string
+	)
+	err = WithTx(func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`SELECT account_id, role FROM password_resets
+			 WHERE token_hash = ? AND used = 0 AND expires_at > ?`,
+			hashRefreshToken(req.Token), nowUTC()).Scan(&accountID, &role)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE password_resets SET used = 1 WHERE token_hash = ?",
+			hashRefreshToken(req.Token)); err != nil {
+			return err
+		}
+		table, _ := accountTable(role)
+		_, err = tx.Exec("UPDATE "+table+" SET password = ? WHERE id = ?", hashed, accountID)
+		return err
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusUnauthorized, "token reset tidak valid atau sudah kadaluarsa")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// A password reset usually means the old credentials are suspect.
+	if err := revokeRefreshTokens(accountID, role); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "password berhasil direset")
+}
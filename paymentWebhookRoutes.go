@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+func registerPaymentWebhookRoutes(r *Router) {
+	r.POST("/api/v1/payments/webhooks/:provider", HandlePaymentWebhook)
+	r.GET("/api/v1/admin/webhook-events", RoleMiddleware("admin")(GetWebhookEvents))
+}
+
+// verifyWebhookSignature checks the provider's signature over the raw body.
+// Each provider gets its own scheme and secret; unknown providers are
+// rejected outright.
+func verifyWebhookSignature(provider string, body []byte, r *http.Request) (bool, error) {
+	switch provider {
+	case "qris":
+		mac := hmac.New(sha256.New, []byte(config.QRISWebhookSecret))
+		mac.Write(body)
+		expected := hex.EncodeToString(mac.Sum(nil))
+		return hmac.Equal([]byte(expected), []byte(r.Header.Get("X-Callback-Signature"))), nil
+	default:
+		return false, fmt.Errorf("provider %s tidak dikenal", provider)
+	}
+}
+
+// processWebhookEvent applies one verified event. Errors here mean the event
+// is well-signed but we could not act on it; the caller dead-letters it.
+func processWebhookEvent(provider string, event paymentWebhookEvent) error {
+	switch {
+	case provider == "qris" && event.Type == "payment.paid":
+		_, err := applyQrisPaid(event.ExternalID)
+		return err
+	default:
+		return fmt.Errorf("tipe event %s tidak didukung", event.Type)
+	}
+}
+
+type paymentWebhookEvent struct {
+	EventID    string `json:"event_id"`
+	Type       string `json:"type"`
+	ExternalID string `json:"external_id"`
+}
+
+// HandlePaymentWebhook is the shared entry point for payment provider
+// callbacks. Signatures are checked per provider, event ids are recorded so
+// retries never apply twice, and events we cannot process are kept with their
+// error for manual replay instead of being dropped.
+func HandlePaymentWebhook(w http.ResponseWriter, r *http.Request) {
+	provider := Param(r, "provider")
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<16))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak terbaca")
+		return
+	}
+	ok, err := verifyWebhookSignature(provider, body, r)
+	if err != nil {
+		respondError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "signature tidak valid")
+		return
+	}
+	var event paymentWebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil || event.EventID == "" {
+		respondError(w, http.StatusBadRequest, "payload tidak valid")
+		return
+	}
+	res, err := db.Exec(
+		"INSERT IGNORE INTO webhook_events (provider, event_id, payload, status, created_at) VALUES (?, ?, ?, 'received', ?)",
+		provider, event.EventID, string(body), nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// Seen before; acknowledge without reprocessing.
+		respondMessage(w, http.StatusOK, "event sudah diproses")
+		return
+	}
+	if procErr := processWebhookEvent(provider, event); procErr != nil {
+		// Dead-letter: keep the payload and the reason, but still acknowledge
+		// so the provider does not retry an event we have safely stored.
+		if _, err := db.Exec(
+			"UPDATE webhook_events SET status = 'failed', last_error = ? WHERE provider = ? AND event_id = ?",
+			procErr.Error(), provider, event.EventID); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondMessage(w, http.StatusOK, "event disimpan untuk diproses ulang")
+		return
+	}
+	if _, err := db.Exec(
+		"UPDATE webhook_events SET status = 'processed' WHERE provider = ? AND event_id = ?",
+		provider, event.EventID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "event diproses")
+}
+
+// GetWebhookEvents lists received webhook events for admins; ?status=failed
+// surfaces the dead-letter queue.
+func GetWebhookEvents(w http.ResponseWriter, r *http.Request) {
+	query := "SELECT id, provider, event_id, payload, status, COALESCE(last_error, ''), created_at FROM webhook_events"
+	args := []any{}
+	if s := r.URL.Query().Get("status"); s != "" {
+		query += " WHERE status = ?"
+		args = append(args, s)
+	}
+	query += " ORDER BY id DESC LIMIT 100"
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	type webhookEventRow struct {
+		ID        int64  `json:"id"`
+		Provider  string `json:"provider"`
+		EventID   string `json:"event_id"`
+		Payload   string `json:"payload"`
+		Status    string `json:"status"`
+		LastError string `json:"last_error,omitempty"`
+		CreatedAt string `json:"created_at"`
+	}
+	events := []webhookEventRow{}
+	for rows.Next() {
+		var (
+			e         webhookEventRow
+			createdAt = nowUTC()
+		)
+		if err := rows.Scan(&e.ID, &e.Provider, &e.EventID, &e.Payload, &e.Status, &e.LastError, &createdAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		e.CreatedAt = displayTime(createdAt)
+		events = append(events, e)
+	}
+	respondJSON(w, http.StatusOK, events)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerPointsRoutes(r *Router) {
+	r.GET("/api/v1/me/points", RoleMiddleware("user")(GetMyPoints))
+	r.GET("/api/v1/me/points/history", RoleMiddleware("user")(GetMyPointHistory))
+}
+
+var errInsufficientPoints = errors.New("points tidak cukup")
+
+// PointEvent is one recorded change to a user's points balance.
+type PointEvent struct {
+	ID        int64  `json:"id"`
+	UserID    int64  `json:"user_id"`
+	Delta     int    `json:"delta"`
+	Reason    string `json:"reason"`
+	RefID     int64  `json:"ref_id"`
+	CreatedAt string `json:"created_at"`
+}
+
+// adjustPoints moves a user's points balance and writes the matching ledger
+// entry, mirroring adjustHearts. The update only applies when the balance
+// stays at or above zero; it reports whether anything changed.
+func adjustPoints(q dbtx, userID int64, delta int, reason string, refID int64) (bool, error) {
+	res, err := q.Exec(
+		"UPDATE users SET points = points + ? WHERE id = ? AND points + ? >= 0",
+		delta, userID, delta)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return false, nil
+	}
+	if _, err := q.Exec(
+		"INSERT INTO point_events (user_id, delta, reason, ref_id, created_at) VALUES (?, ?, ?, ?, ?)",
+		userID, delta, reason, refID, nowUTC()); err != nil {
+		log.Printf("gagal mencatat point event user %d: %v", userID, err)
+	}
+	return true, nil
+}
+
+// awardOrderPoints grants loyalty points for a completed order: one point per
+// POINTS_EARN_PER of the order total. Failures are logged because points must
+// never break payment confirmation.
+func awardOrderPoints(userID, orderID int64) {
+	if config.PointsEarnPer <= 0 {
+		return
+	}
+	var total float64
+	if err := db.QueryRow("SELECT total_price FROM orders WHERE id = ?", orderID).
+		Scan(&total); err != nil {
+		log.Printf("gagal cek total order %d untuk points: %v", orderID, err)
+		return
+	}
+	points := int(total / config.PointsEarnPer)
+	if points <= 0 {
+		return
+	}
+	if _, err := adjustPoints(db, userID, points, "order_completed", orderID); err != nil {
+		log.Printf("gagal memberi points user %d: %v", userID, err)
+		return
+	}
+	createNotification(userID, "Points bertambah",
+		fmt.Sprintf("Kamu mendapat %d points dari order #%d.", points, orderID))
+}
+
+// pointsDiscount converts a redeemed points amount into rupiah.
+func pointsDiscount(points int) float64 {
+	return float64(points) * config.PointValue
+}
+
+// GetMyPoints returns the caller's balance and what it is worth at checkout.
+func GetMyPoints(w http.ResponseWriter, r *http.Request) {
+	var points int
+	if err := db.QueryRow("SELECT points FROM users WHERE id = ?", currentUserID(r)).
+		Scan(&points); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"points":       points,
+		"redeem_value": pointsDiscount(points),
+	})
+}
+
+// GetMyPointHistory lists the caller's points changes newest-first, with
+// optional keyset pagination via ?limit= and ?cursor=.
+func GetMyPointHistory(w http.ResponseWriter, r *http.Request) {
+	k := keysetParams(r)
+	query := "SELECT id, user_id, delta, reason, ref_id, created_at FROM point_events WHERE user_id = ?"
+	args := []any{currentUserID(r)}
+	if k.Enabled {
+		if k.Cursor > 0 {
+			query += " AND id < ?"
+			args = append(args, k.Cursor)
+		}
+		query += " ORDER BY id DESC LIMIT ?"
+		args = append(args, k.Limit)
+	} else {
+		query += " ORDER BY id DESC LIMIT 50"
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	events := []PointEvent{}
+	for rows.Next() {
+		var (
+			e       PointEvent
+			created time.Time
+		)
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Delta, &e.Reason, &e.RefID, &created); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		e.CreatedAt = displayTime(created)
+		events = append(events, e)
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(events) > 0 {
+			lastID = events[len(events)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       events,
+			"next_cursor": nextCursor(lastID, len(events), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, events)
+}
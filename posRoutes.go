@@ -0,0 +1,215 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerPosRoutes(r *Router) {
+	r.GET("/api/v1/pos/products", RoleMiddleware("cashier", "admin")(PosLookupProduct))
+	r.POST("/api/v1/pos/sales", RoleMiddleware("cashier", "admin")(CreatePosSale))
+	r.GET("/api/v1/pos/sales/:id/receipt", RoleMiddleware("cashier", "admin")(GetPosReceipt))
+}
+
+// PosSale is an in-store sale rung up by a cashier. It bypasses carts,
+// reservations and hearts entirely.
+type PosSale struct {
+	ID            int64         `json:"id"`
+	CashierID     int64         `json:"cashier_id"`
+	TotalPrice    float64       `json:"total_price"`
+	PaymentMethod string        `json:"payment_method"`
+	AmountPaid    float64       `json:"amount_paid"`
+	ChangeDue     float64       `json:"change_due"`
+	CreatedAt     time.Time     `json:"created_at"`
+	Items         []PosSaleItem `json:"items,omitempty"`
+}
+
+// PosSaleItem is one line of an in-store sale.
+type PosSaleItem struct {
+	ID          int64   `json:"id"`
+	SaleID      int64   `json:"sale_id"`
+	ProductID   int64   `json:"product_id"`
+	VariantID   *int64  `json:"variant_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+}
+
+// PosLookupProduct finds a product by barcode (?barcode=) or id (?id=) so the
+// cashier can scan items into a sale.
+func PosLookupProduct(w http.ResponseWriter, r *http.Request) {
+	barcode := r.URL.Query().Get("barcode")
+	var row *sql.Row
+	if barcode != "" {
+		row = db.QueryRow("SELECT "+productColumns+" FROM products WHERE barcode = ?", barcode)
+	} else if id := queryInt(r, "id", 0); id > 0 {
+		row = db.QueryRow("SELECT "+productColumns+" FROM products WHERE id = ?", id)
+	} else {
+		respondError(w, http.StatusBadRequest, "barcode atau id wajib diisi")
+		return
+	}
+	p, err := scanProduct(row)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, p)
+}
+
+// CreatePosSale records an in-store sale: prices come from the catalog, stock
+// is decremented immediately, and cash sales get change calculated.
+func CreatePosSale(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Items []struct {
+			ProductID int64  `json:"product_id"`
+			VariantID *int64 `json:"variant_id"`
+			Quantity  int    `json:"quantity"`
+		} `json:"items"`
+		PaymentMethod string  `json:"payment_method"`
+		AmountPaid    float64 `json:"amount_paid"`
+	}
+	if err := decodeBody(r, &req); err != nil || len(req.Items) == 0 {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.PaymentMethod != "cash" && req.PaymentMethod != "qris" {
+		respondError(w, http.StatusBadRequest, "payment_method harus cash atau qris")
+		return
+	}
+	type line struct {
+		productID int64
+		variantID *int64
+		quantity  int
+		price     float64
+	}
+	var (
+		lines []line
+		total float64
+	)
+	for _, it := range req.Items {
+		if it.Quantity < 1 {
+			respondError(w, http.StatusBadRequest, "quantity minimal 1")
+			return
+		}
+		price, stock, _, err := resolveItemPrice(it.ProductID, it.VariantID)
+		if err == errVariantRequired {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		} else if err == sql.ErrNoRows {
+			respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+			return
+		} else if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if stock < it.Quantity {
+			respondError(w, http.StatusBadRequest, "stock tidak mencukupi")
+			return
+		}
+		lines = append(lines, line{it.ProductID, it.VariantID, it.Quantity, price})
+		total += price * float64(it.Quantity)
+	}
+	var change float64
+	if req.PaymentMethod == "cash" {
+		if req.AmountPaid < total {
+			respondError(w, http.StatusBadRequest, "uang yang dibayarkan kurang dari total")
+			return
+		}
+		change = req.AmountPaid - total
+	} else {
+		req.AmountPaid = total
+	}
+	var saleID int64
+	err := WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			`INSERT INTO pos_sales (cashier_id, total_price, payment_method, amount_paid, change_due, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+			currentUserID(r), total, req.PaymentMethod, req.AmountPaid, change, nowUTC())
+		if err != nil {
+			return err
+		}
+		saleID, _ = res.LastInsertId()
+		for _, l := range lines {
+			if _, err := tx.Exec(
+				"INSERT INTO pos_sale_items (sale_id, product_id, variant_id, quantity, price) VALUES (?, ?, ?, ?, ?)",
+				saleID, l.productID, l.variantID, l.quantity, l.price); err != nil {
+				return err
+			}
+			if err := stockSvc.Reserve(tx, l.productID, l.variantID, l.quantity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"id":          saleID,
+		"total_price": total,
+		"amount_paid": req.AmountPaid,
+		"change_due":  change,
+		"message":     "penjualan berhasil dicatat",
+	})
+}
+
+// GetPosReceipt returns print-ready data for a completed sale.
+func GetPosReceipt(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var sale PosSale
+	err = db.QueryRow(
+		`SELECT id, cashier_id, total_price, payment_method, amount_paid, change_due, created_at
+		 FROM pos_sales WHERE id = ?`, id).
+		Scan(&sale.ID, &sale.CashierID, &sale.TotalPrice, &sale.PaymentMethod,
+			&sale.AmountPaid, &sale.ChangeDue, &sale.CreatedAt)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "penjualan tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rows, err := db.Query(
+		`SELECT psi.id, psi.sale_id, psi.product_id, psi.variant_id, p.name, psi.quantity, psi.price
+		 FROM pos_sale_items psi
+		 JOIN products p ON p.id = psi.product_id
+		 WHERE psi.sale_id = ?`, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	sale.Items = []PosSaleItem{}
+	for rows.Next() {
+		var (
+			it        PosSaleItem
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.SaleID, &it.ProductID, &variantID,
+			&it.ProductName, &it.Quantity, &it.Price); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		sale.Items = append(sale.Items, it)
+	}
+	var cashierName string
+	db.QueryRow("SELECT username FROM employees WHERE id = ?", sale.CashierID).Scan(&cashierName)
+	respondJSON(w, http.StatusOK, map[string]any{
+		"sale":     sale,
+		"cashier":  cashierName,
+		"sold_at":  displayTime(sale.CreatedAt),
+		"timezone": config.DisplayTZ,
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"log"
+	"math"
+	"time"
+)
+
+// effectivePrice applies the pricing rules in one place so clients never
+// reimplement them. Today the only rule is the is_discounted flag; flash
+// sales and group pricing should hook in here when they land. Returns the
+// price to charge and the discount as a percentage of the list price.
+func effectivePrice(price float64, isDiscounted bool, discountPrice float64) (float64, float64) {
+	if !isDiscounted || discountPrice <= 0 || discountPrice >= price {
+		return price, 0
+	}
+	percent := math.Round((price-discountPrice)/price*10000) / 100
+	return discountPrice, percent
+}
+
+// discountWindowOpen reports whether a scheduled discount applies right now.
+// A nil bound leaves that side open, so discounts without a schedule keep
+// working exactly as before.
+func discountWindowOpen(startsAt, endsAt *time.Time) bool {
+	now := nowUTC()
+	if startsAt != nil && now.Before(*startsAt) {
+		return false
+	}
+	if endsAt != nil && !now.Before(*endsAt) {
+		return false
+	}
+	return true
+}
+
+// applyProductPricing fills the computed pricing fields on a scanned product,
+// letting an active category campaign undercut the product's own discount.
+func applyProductPricing(p *Product) {
+	active := p.IsDiscounted && discountWindowOpen(p.DiscountStartsAt, p.DiscountEndsAt)
+	p.EffectivePrice, p.DiscountPercent = effectivePrice(p.Price, active, p.DiscountPrice)
+	if price, percent := campaignPrice(p.CategoryID, p.Price, p.EffectivePrice); percent > 0 {
+		p.EffectivePrice = price
+		p.DiscountPercent = percent
+	}
+}
+
+// applyVariantPricing fills the computed pricing fields on a scanned variant.
+func applyVariantPricing(v *ProductVariant) {
+	active := v.IsDiscounted && discountWindowOpen(v.DiscountStartsAt, v.DiscountEndsAt)
+	v.EffectivePrice, v.DiscountPercent = effectivePrice(v.Price, active, v.DiscountPrice)
+}
+
+// StartDiscountExpiryWorker clears discounts whose window has closed so the
+// flags in the database match what the pricing rules already stopped
+// honouring.
+func StartDiscountExpiryWorker() {
+	go func() {
+		for range time.Tick(time.Hour) {
+			clearExpiredDiscounts()
+		}
+	}()
+}
+
+func clearExpiredDiscounts() {
+	for _, table := range []string{"products", "product_variants"} {
+		res, err := db.Exec(
+			"UPDATE "+table+` SET is_discounted = 0, discount_price = 0,
+				discount_starts_at = NULL, discount_ends_at = NULL
+			 WHERE is_discounted = 1 AND discount_ends_at IS NOT NULL AND discount_ends_at < ?`, nowUTC())
+		if err != nil {
+			log.Printf("gagal bersihkan diskon kadaluarsa di %s: %v", table, err)
+			continue
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			log.Printf("%d diskon kadaluarsa dibersihkan dari %s", n, table)
+		}
+	}
+}
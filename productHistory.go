@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+)
+
+func registerProductHistoryRoutes(r *Router) {
+	r.GET("/api/v1/admin/products/:id/history", RoleMiddleware("admin")(GetProductHistory))
+}
+
+// ProductChange is one field-level diff recorded when a product or one of its
+// variants is edited, so price questions ("who changed this and when?") can
+// be answered without digging through full audit snapshots.
+type ProductChange struct {
+	ID        int64  `json:"id"`
+	ProductID int64  `json:"product_id"`
+	VariantID *int64 `json:"variant_id"`
+	ActorID   int64  `json:"actor_id"`
+	ActorRole string `json:"actor_role"`
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	CreatedAt string `json:"created_at"`
+}
+
+// changeSkipFields are computed or bookkeeping fields that would flood the
+// history with noise on every edit.
+var changeSkipFields = map[string]bool{
+	"id":               true,
+	"product_id":       true,
+	"effective_price":  true,
+	"discount_percent": true,
+	"wishlist_count":   true,
+	"version":          true,
+	"created_at":       true,
+	"updated_at":       true,
+}
+
+func changeValue(v any) string {
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+// diffFields compares two snapshots through their JSON form and returns the
+// changed fields as {field, old, new} triples, sorted for stable output.
+func diffFields(before, after any) [][3]string {
+	toMap := func(v any) map[string]any {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		m := map[string]any{}
+		if err := json.Unmarshal(raw, &m); err != nil {
+			return nil
+		}
+		return m
+	}
+	beforeMap, afterMap := toMap(before), toMap(after)
+	if beforeMap == nil || afterMap == nil {
+		return nil
+	}
+	fields := []string{}
+	for field := range afterMap {
+		if !changeSkipFields[field] {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	var diffs [][3]string
+	for _, field := range fields {
+		oldValue, newValue := changeValue(beforeMap[field]), changeValue(afterMap[field])
+		if oldValue != newValue {
+			diffs = append(diffs, [3]string{field, oldValue, newValue})
+		}
+	}
+	return diffs
+}
+
+// recordProductChanges stores the field-level diff of one edit. variantID is
+// nil for edits of the product itself. Failures are logged, never surfaced —
+// same contract as the audit log.
+func recordProductChanges(r *http.Request, productID int64, variantID *int64, before, after any) {
+	diffs := diffFields(before, after)
+	if len(diffs) == 0 {
+		return
+	}
+	var (
+		actorID int64
+		role    string
+	)
+	if claims := currentClaims(r); claims != nil {
+		actorID, role = claims.ID, claims.Role
+	}
+	for _, d := range diffs {
+		_, err := db.Exec(
+			`INSERT INTO product_changes (product_id, variant_id, actor_id, actor_role, field, old_value, new_value, created_at)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			productID, variantID, actorID, role, d[0], d[1], d[2], nowUTC())
+		if err != nil {
+			log.Printf("gagal mencatat perubahan produk %d: %v", productID, err)
+			return
+		}
+	}
+}
+
+// GetProductHistory lists the recorded changes of one product (including its
+// variants) newest first, with the usual keyset pagination.
+func GetProductHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	k := keysetParams(r)
+	query := `SELECT id, product_id, variant_id, actor_id, actor_role, field, old_value, new_value, created_at
+	          FROM product_changes WHERE product_id = ?`
+	args := []any{id}
+	if k.Enabled && k.Cursor > 0 {
+		query += " AND id < ?"
+		args = append(args, k.Cursor)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	if k.Enabled {
+		args = append(args, k.Limit)
+	} else {
+		args = append(args, 50)
+	}
+	rows, err := readOnlyDB().Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	changes := []ProductChange{}
+	for rows.Next() {
+		var (
+			c         ProductChange
+			createdAt time.Time
+		)
+		if err := rows.Scan(&c.ID, &c.ProductID, &c.VariantID, &c.ActorID, &c.ActorRole,
+			&c.Field, &c.OldValue, &c.NewValue, &createdAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		c.CreatedAt = displayTime(createdAt)
+		changes = append(changes, c)
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(changes) > 0 {
+			lastID = changes[len(changes)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       changes,
+			"next_cursor": nextCursor(lastID, len(changes), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, changes)
+}
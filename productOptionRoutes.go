@@ -0,0 +1,244 @@
+package main
+
+import "net/http"
+
+func registerProductOptionRoutes(r *Router) {
+	r.GET("/api/v1/products/:id/options", GetProductOptions)
+	r.POST("/api/v1/products/:id/options", RoleMiddleware("admin")(CreateProductOption))
+	r.DELETE("/api/v1/products/:id/options/:optionID", RoleMiddleware("admin")(DeleteProductOption))
+}
+
+// ProductOption is a per-product customization the buyer fills in at
+// add-to-cart: free text (engraving, size note) or a yes/no add-on. Either
+// kind may carry a surcharge that is priced into the line.
+type ProductOption struct {
+	ID        int64   `json:"id"`
+	ProductID int64   `json:"product_id"`
+	Name      string  `json:"name"`
+	Type      string  `json:"type"`
+	Surcharge float64 `json:"surcharge"`
+	Required  bool    `json:"required"`
+}
+
+// fetchProductOptions returns a product's options keyed by id.
+func fetchProductOptions(productID int64) (map[int64]ProductOption, error) {
+	rows, err := db.Query(
+		"SELECT id, product_id, name, type, surcharge, required FROM product_options WHERE product_id = ?",
+		productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	options := map[int64]ProductOption{}
+	for rows.Next() {
+		var o ProductOption
+		if err := rows.Scan(&o.ID, &o.ProductID, &o.Name, &o.Type, &o.Surcharge, &o.Required); err != nil {
+			return nil, err
+		}
+		options[o.ID] = o
+	}
+	return options, rows.Err()
+}
+
+// GetProductOptions lists the customization options of a product.
+func GetProductOptions(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	options, err := fetchProductOptions(id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	list := []ProductOption{}
+	for _, o := range options {
+		list = append(list, o)
+	}
+	respondJSON(w, http.StatusOK, list)
+}
+
+// CreateProductOption adds a customization option to a product.
+func CreateProductOption(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Name      string  `json:"name"`
+		Type      string  `json:"type"`
+		Surcharge float64 `json:"surcharge"`
+		Required  bool    `json:"required"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Name == "" {
+		respondError(w, http.StatusBadRequest, "name wajib diisi")
+		return
+	}
+	if req.Type != "text" && req.Type != "addon" {
+		respondError(w, http.StatusBadRequest, "type harus text atau addon")
+		return
+	}
+	if req.Surcharge < 0 {
+		respondError(w, http.StatusBadRequest, "surcharge tidak boleh negatif")
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", id).Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists == 0 {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	}
+	res, err := db.Exec(
+		"INSERT INTO product_options (product_id, name, type, surcharge, required) VALUES (?, ?, ?, ?, ?)",
+		id, req.Name, req.Type, req.Surcharge, req.Required)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	optionID, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": optionID, "message": "opsi produk dibuat"})
+}
+
+// DeleteProductOption removes an option; lines already carrying its value
+// keep their denormalized copy.
+func DeleteProductOption(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	optionID, err := paramInt(r, "optionID")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "optionID tidak valid")
+		return
+	}
+	res, err := db.Exec("DELETE FROM product_options WHERE id = ? AND product_id = ?", optionID, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "opsi tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "opsi produk dihapus")
+}
+
+// chosenOption is one option value submitted at add-to-cart.
+type chosenOption struct {
+	OptionID int64  `json:"option_id"`
+	Value    string `json:"value"`
+}
+
+// validateChosenOptions checks submitted option values against the product's
+// defined options and returns the rows to store plus the total surcharge per
+// unit. It returns a message for the client when the input is invalid.
+func validateChosenOptions(productID int64, chosen []chosenOption) ([]ItemOption, float64, string, error) {
+	defined, err := fetchProductOptions(productID)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	seen := map[int64]bool{}
+	var (
+		rows      []ItemOption
+		surcharge float64
+	)
+	for _, c := range chosen {
+		o, ok := defined[c.OptionID]
+		if !ok {
+			return nil, 0, "opsi tidak dikenal untuk produk ini", nil
+		}
+		if seen[c.OptionID] {
+			return nil, 0, "opsi yang sama dipilih dua kali", nil
+		}
+		seen[c.OptionID] = true
+		if o.Type == "text" && c.Value == "" {
+			return nil, 0, "opsi " + o.Name + " wajib diisi", nil
+		}
+		value := c.Value
+		if o.Type == "addon" {
+			value = "ya"
+		}
+		rows = append(rows, ItemOption{OptionID: o.ID, Name: o.Name, Value: value, Surcharge: o.Surcharge})
+		surcharge += o.Surcharge
+	}
+	for _, o := range defined {
+		if o.Required && !seen[o.ID] {
+			return nil, 0, "opsi " + o.Name + " wajib diisi", nil
+		}
+	}
+	return rows, surcharge, "", nil
+}
+
+// ItemOption is a chosen option value denormalized onto a cart or order
+// line, so later edits to the product's options never rewrite history.
+type ItemOption struct {
+	OptionID  int64   `json:"option_id"`
+	Name      string  `json:"name"`
+	Value     string  `json:"value"`
+	Surcharge float64 `json:"surcharge"`
+}
+
+// insertCartItemOptions stores the chosen options of one cart line.
+func insertCartItemOptions(q dbtx, cartItemID int64, options []ItemOption) error {
+	for _, o := range options {
+		if _, err := q.Exec(
+			"INSERT INTO cart_item_options (cart_item_id, option_id, name, value, surcharge) VALUES (?, ?, ?, ?, ?)",
+			cartItemID, o.OptionID, o.Name, o.Value, o.Surcharge); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchItemOptions loads the stored options of one cart or order line from
+// the given table.
+func fetchItemOptions(table string, itemID int64) ([]ItemOption, error) {
+	var column string
+	switch table {
+	case "cart_item_options":
+		column = "cart_item_id"
+	case "order_item_options":
+		column = "order_item_id"
+	}
+	rows, err := db.Query(
+		"SELECT option_id, name, value, surcharge FROM "+table+" WHERE "+column+" = ?", itemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	options := []ItemOption{}
+	for rows.Next() {
+		var o ItemOption
+		if err := rows.Scan(&o.OptionID, &o.Name, &o.Value, &o.Surcharge); err != nil {
+			return nil, err
+		}
+		options = append(options, o)
+	}
+	return options, rows.Err()
+}
+
+// copyCartItemOptions copies a cart line's options onto the order line
+// created from it, inside the caller's transaction.
+func copyCartItemOptions(q dbtx, cartItemID, orderItemID int64) error {
+	_, err := q.Exec(
+		`INSERT INTO order_item_options (order_item_id, option_id, name, value, surcharge)
+		 SELECT ?, option_id, name, value, surcharge FROM cart_item_options WHERE cart_item_id = ?`,
+		orderItemID, cartItemID)
+	return err
+}
+
+// cartItemOptionSurcharge returns the per-unit surcharge already stored on a
+// cart line's options, for price recomputation on quantity changes.
+func cartItemOptionSurcharge(cartItemID int64) (float64, error) {
+	var surcharge float64
+	err := db.QueryRow(
+		"SELECT COALESCE(SUM(surcharge), 0) FROM cart_item_options WHERE cart_item_id = ?",
+		cartItemID).Scan(&surcharge)
+	return surcharge, err
+}
@@ -0,0 +1,974 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func registerProductRoutes(r *Router) {
+	r.GET("/api/v1/products", GetAllProducts)
+	// The static lookup path is registered before :id so it wins the match.
+	r.GET("/api/v1/products/lookup", LookupProduct)
+	r.GET("/api/v1/products/:id", GetProductDetail)
+	r.GET("/api/v1/products/:id/variants", GetProductVariants)
+	r.GET("/api/v1/products/:id/images", GetProductImages)
+	r.POST("/api/v1/products", PermissionMiddleware("products:write")(CreateProduct))
+	r.PUT("/api/v1/products/:id", PermissionMiddleware("products:write")(UpdateProduct))
+	r.POST("/api/v1/products/:id/publish", PermissionMiddleware("products:write")(PublishProduct))
+	r.DELETE("/api/v1/products/:id", PermissionMiddleware("products:write")(DeleteProduct))
+	r.POST("/api/v1/products/:id/variants", PermissionMiddleware("products:write")(CreateProductVariant))
+	r.PUT("/api/v1/variants/:id", PermissionMiddleware("products:write")(UpdateProductVariant))
+	r.DELETE("/api/v1/variants/:id", PermissionMiddleware("products:write")(DeleteProductVariant))
+	r.POST("/api/v1/products/:id/images", PermissionMiddleware("products:write")(CreateProductImage))
+	r.DELETE("/api/v1/images/:id", PermissionMiddleware("products:write")(DeleteProductImage))
+}
+
+func scanProduct(row interface{ Scan(...any) error }) (Product, error) {
+	var (
+		p                    Product
+		barcode, sku         sql.NullString
+		discStarts, discEnds sql.NullTime
+		expected             sql.NullTime
+		dims                 [4]sql.NullInt64
+	)
+	err := row.Scan(&p.ID, &p.CategoryID, &p.Name, &p.Description, &barcode, &sku, &p.Price, &p.Stock,
+		&p.LowStockThreshold, &p.IsVarians, &p.IsDiscounted, &p.DiscountPrice, &discStarts, &discEnds,
+		&p.IsService, &p.IsDigital, &p.IsPreorder,
+		&expected, &dims[0], &dims[1], &dims[2], &dims[3], &p.Unit, &p.Status, &p.CreatedAt, &p.UpdatedAt)
+	if barcode.Valid {
+		p.Barcode = &barcode.String
+	}
+	if sku.Valid {
+		p.SKU = &sku.String
+	}
+	if discStarts.Valid {
+		p.DiscountStartsAt = &discStarts.Time
+	}
+	if discEnds.Valid {
+		p.DiscountEndsAt = &discEnds.Time
+	}
+	if expected.Valid {
+		p.ExpectedAvailableAt = &expected.Time
+	}
+	for i, target := range []**int{&p.WeightGrams, &p.LengthMM, &p.WidthMM, &p.HeightMM} {
+		if dims[i].Valid {
+			v := int(dims[i].Int64)
+			*target = &v
+		}
+	}
+	applyProductPricing(&p)
+	return p, err
+}
+
+const productColumns = "id, category_id, name, description, barcode, sku, price, stock, low_stock_threshold, is_varians, is_discounted, discount_price, discount_starts_at, discount_ends_at, is_service, is_digital, is_preorder, expected_available_at, weight_grams, length_mm, width_mm, height_mm, unit, status, created_at, updated_at"
+
+// prefixedProductColumns qualifies every product column with a table alias for
+// use in JOIN queries.
+func prefixedProductColumns(alias string) string {
+	return alias + "." + strings.ReplaceAll(productColumns, ", ", ", "+alias+".")
+}
+
+// productSortKeys maps ?sort= keys for the catalog to their columns.
+var productSortKeys = map[string]string{
+	"id":         "products.id",
+	"name":       "products.name",
+	"price":      "products.price",
+	"stock":      "products.stock",
+	"created_at": "products.created_at",
+}
+
+// productStatuses are the visibility states a product moves through.
+var productStatuses = map[string]bool{
+	"draft":     true,
+	"published": true,
+	"archived":  true,
+}
+
+// publishedOnly reports whether the caller may only see published products:
+// anonymous visitors and regular users, as opposed to staff.
+func publishedOnly(r *http.Request) bool {
+	claims := currentClaims(r)
+	return claims == nil || claims.Role == "user"
+}
+
+// productFilters translates the catalog's filter query parameters into SQL
+// conditions. Price bounds also match any live variant inside the range, so
+// variant-priced products don't vanish from price searches. Non-staff callers
+// are pinned to published products; staff see everything and can narrow with
+// ?status=.
+func productFilters(r *http.Request) (conds []string, args []any, err error) {
+	q := r.URL.Query()
+	if publishedOnly(r) {
+		conds = append(conds, "products.status = 'published'")
+	} else if v := q.Get("status"); v != "" {
+		if !productStatuses[v] {
+			return nil, nil, fmt.Errorf("status %q tidak dikenal", v)
+		}
+		conds = append(conds, "products.status = ?")
+		args = append(args, v)
+	}
+	if c := q.Get("category_id"); c != "" {
+		n, convErr := strconv.ParseInt(c, 10, 64)
+		if convErr != nil {
+			return nil, nil, fmt.Errorf("category_id tidak valid")
+		}
+		conds = append(conds, "products.category_id = ?")
+		args = append(args, n)
+	}
+	minStr, maxStr := q.Get("min_price"), q.Get("max_price")
+	if minStr != "" || maxStr != "" {
+		productCond, variantCond := "products.is_varians = 0", "v.deleted_at IS NULL"
+		var priceArgs []any
+		if minStr != "" {
+			min, convErr := strconv.ParseFloat(minStr, 64)
+			if convErr != nil {
+				return nil, nil, fmt.Errorf("min_price tidak valid")
+			}
+			productCond += " AND products.price >= ?"
+			variantCond += " AND v.price >= ?"
+			priceArgs = append(priceArgs, min)
+		}
+		if maxStr != "" {
+			max, convErr := strconv.ParseFloat(maxStr, 64)
+			if convErr != nil {
+				return nil, nil, fmt.Errorf("max_price tidak valid")
+			}
+			productCond += " AND products.price <= ?"
+			variantCond += " AND v.price <= ?"
+			priceArgs = append(priceArgs, max)
+		}
+		conds = append(conds, "(("+productCond+") OR EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id AND "+variantCond+"))")
+		args = append(args, priceArgs...)
+		args = append(args, priceArgs...)
+	}
+	if q.Get("in_stock") == "true" {
+		conds = append(conds,
+			`((products.is_varians = 0 AND products.stock > 0)
+			  OR EXISTS (SELECT 1 FROM product_variants v
+			             WHERE v.product_id = products.id AND v.deleted_at IS NULL AND v.stock > 0))`)
+	}
+	if v := q.Get("is_discounted"); v != "" {
+		conds = append(conds, "products.is_discounted = ?")
+		args = append(args, v == "true")
+	}
+	if v := q.Get("is_service"); v != "" {
+		conds = append(conds, "products.is_service = ?")
+		args = append(args, v == "true")
+	}
+	return conds, args, nil
+}
+
+// GetAllProducts lists the catalog. Filters (?category_id=, ?min_price=,
+// ?max_price=, ?in_stock=true, ?is_discounted=, ?is_service=) combine
+// freely. ?page=/?limit=/?sort= give numbered pages with total counts in the
+// standard envelope; ?limit=/?cursor= alone keeps the older keyset
+// pagination.
+func GetAllProducts(w http.ResponseWriter, r *http.Request) {
+	pg, err := pageParams(r, productSortKeys, "products.id DESC")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	conds, condArgs, err := productFilters(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !includeDeleted(r) {
+		conds = append(conds, "products.deleted_at IS NULL")
+	}
+	k := keysetParams(r)
+	query := `SELECT ` + productColumns + `,
+			(SELECT COUNT(*) FROM wishlists w WHERE w.product_id = products.id) AS wishlist_count
+		 FROM products`
+	args := []any{}
+	var total int64
+	if pg.Enabled {
+		countQuery := "SELECT COUNT(*) FROM products"
+		if len(conds) > 0 {
+			countQuery += " WHERE " + strings.Join(conds, " AND ")
+		}
+		if err := readOnlyDB().QueryRow(countQuery, condArgs...).Scan(&total); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	} else if k.Enabled && k.Cursor > 0 {
+		conds = append(conds, "products.id < ?")
+		condArgs = append(condArgs, k.Cursor)
+	}
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+		args = append(args, condArgs...)
+	}
+	if pg.Enabled {
+		query += " ORDER BY " + pg.OrderBy + " LIMIT ? OFFSET ?"
+		args = append(args, pg.Limit, pg.offset())
+	} else if k.Enabled {
+		query += " ORDER BY products.id DESC LIMIT ?"
+		args = append(args, k.Limit)
+	}
+	rows, err := readOnlyDB().Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	products := []Product{}
+	for rows.Next() {
+		var (
+			p                    Product
+			barcode, sku         sql.NullString
+			discStarts, discEnds sql.NullTime
+			expected             sql.NullTime
+			dims                 [4]sql.NullInt64
+		)
+		if err := rows.Scan(&p.ID, &p.CategoryID, &p.Name, &p.Description, &barcode, &sku, &p.Price, &p.Stock,
+			&p.LowStockThreshold, &p.IsVarians, &p.IsDiscounted, &p.DiscountPrice, &discStarts, &discEnds,
+			&p.IsService, &p.IsDigital, &p.IsPreorder,
+			&expected, &dims[0], &dims[1], &dims[2], &dims[3], &p.Unit, &p.Status,
+			&p.CreatedAt, &p.UpdatedAt, &p.WishlistCount); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if barcode.Valid {
+			p.Barcode = &barcode.String
+		}
+		if sku.Valid {
+			p.SKU = &sku.String
+		}
+		if discStarts.Valid {
+			p.DiscountStartsAt = &discStarts.Time
+		}
+		if discEnds.Valid {
+			p.DiscountEndsAt = &discEnds.Time
+		}
+		if expected.Valid {
+			p.ExpectedAvailableAt = &expected.Time
+		}
+		for i, target := range []**int{&p.WeightGrams, &p.LengthMM, &p.WidthMM, &p.HeightMM} {
+			if dims[i].Valid {
+				v := int(dims[i].Int64)
+				*target = &v
+			}
+		}
+		applyProductPricing(&p)
+		products = append(products, p)
+	}
+	fields := fieldSet(r)
+	if pg.Enabled {
+		respondJSON(w, http.StatusOK, pageEnvelope(pruneFields(products, fields), pg, total))
+		return
+	}
+	if k.Enabled {
+		var lastID int64
+		if len(products) > 0 {
+			lastID = products[len(products)-1].ID
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"items":       pruneFields(products, fields),
+			"next_cursor": nextCursor(lastID, len(products), k),
+		})
+		return
+	}
+	respondJSON(w, http.StatusOK, pruneFields(products, fields))
+}
+
+// GetProductDetail returns one product together with its variants and
+// images, so clients don't have to stitch three list endpoints together.
+func GetProductDetail(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	detailQuery := "SELECT " + productColumns + " FROM products WHERE id = ?"
+	if !includeDeleted(r) {
+		detailQuery += notDeleted("")
+	}
+	if publishedOnly(r) {
+		detailQuery += " AND status = 'published'"
+	}
+	p, err := scanProduct(readOnlyDB().QueryRow(detailQuery, id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := readOnlyDB().QueryRow(
+		"SELECT COUNT(*) FROM wishlists WHERE product_id = ?", id).Scan(&p.WishlistCount); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	variants, err := fetchProductVariants(id, includeDeleted(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	images, err := fetchProductImages(id, includeDeleted(r), "")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"product":  pruneFields(p, fieldSet(r)),
+		"variants": variants,
+		"images":   images,
+	})
+}
+
+// LookupProduct resolves a scanned code (?barcode= or ?sku=) to its product,
+// checking products first and then variants, so a POS terminal gets an answer
+// in one round trip. Variant hits include the variant alongside its product.
+func LookupProduct(w http.ResponseWriter, r *http.Request) {
+	barcode := r.URL.Query().Get("barcode")
+	sku := r.URL.Query().Get("sku")
+	if barcode == "" && sku == "" {
+		respondError(w, http.StatusBadRequest, "barcode atau sku wajib diisi")
+		return
+	}
+	column, code := "barcode", barcode
+	if barcode == "" {
+		column, code = "sku", sku
+	}
+	statusCond := ""
+	if publishedOnly(r) {
+		statusCond = " AND status = 'published'"
+	}
+	p, err := scanProduct(readOnlyDB().QueryRow(
+		"SELECT "+productColumns+" FROM products WHERE "+column+" = ? AND deleted_at IS NULL"+statusCond, code))
+	if err == nil {
+		respondJSON(w, http.StatusOK, map[string]any{"product": p, "variant": nil})
+		return
+	}
+	if err != sql.ErrNoRows {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var variantID int64
+	err = readOnlyDB().QueryRow(
+		"SELECT id FROM product_variants WHERE "+column+" = ?"+notDeleted(""), code).Scan(&variantID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	v, err := fetchVariant(variantID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	p, err = scanProduct(readOnlyDB().QueryRow(
+		"SELECT "+productColumns+" FROM products WHERE id = ? AND deleted_at IS NULL"+statusCond, v.ProductID))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"product": p, "variant": v})
+}
+
+type productRequest struct {
+	CategoryID          int64      `json:"category_id"`
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	Barcode             *string    `json:"barcode"`
+	SKU                 *string    `json:"sku"`
+	Price               float64    `json:"price"`
+	Stock               int        `json:"stock"`
+	LowStockThreshold   int        `json:"low_stock_threshold"`
+	IsVarians           bool       `json:"is_varians"`
+	IsDiscounted        bool       `json:"is_discounted"`
+	DiscountPrice       float64    `json:"discount_price"`
+	DiscountStartsAt    *time.Time `json:"discount_starts_at"`
+	DiscountEndsAt      *time.Time `json:"discount_ends_at"`
+	IsService           bool       `json:"is_service"`
+	IsDigital           bool       `json:"is_digital"`
+	IsPreorder          bool       `json:"is_preorder"`
+	ExpectedAvailableAt *time.Time `json:"expected_available_at"`
+	WeightGrams         *int       `json:"weight_grams"`
+	LengthMM            *int       `json:"length_mm"`
+	WidthMM             *int       `json:"width_mm"`
+	HeightMM            *int       `json:"height_mm"`
+	Unit                string     `json:"unit"`
+	Status              string     `json:"status"`
+	// UpdatedAt, when sent, is compared against the stored row so concurrent
+	// edits fail with a conflict instead of overwriting each other.
+	UpdatedAt *time.Time `json:"updated_at"`
+}
+
+func (req *productRequest) validate() string {
+	if req.Name == "" {
+		return "nama produk wajib diisi"
+	}
+	if req.Price < 0 || req.DiscountPrice < 0 {
+		return "harga tidak boleh negatif"
+	}
+	if req.Stock < 0 {
+		return "stock tidak boleh negatif"
+	}
+	if req.LowStockThreshold < 0 {
+		return "low_stock_threshold tidak boleh negatif"
+	}
+	if req.DiscountStartsAt != nil && req.DiscountEndsAt != nil && !req.DiscountEndsAt.After(*req.DiscountStartsAt) {
+		return "discount_ends_at harus setelah discount_starts_at"
+	}
+	if req.IsPreorder && req.ExpectedAvailableAt == nil {
+		return "produk pre-order wajib punya expected_available_at"
+	}
+	for _, dim := range []*int{req.WeightGrams, req.LengthMM, req.WidthMM, req.HeightMM} {
+		if dim != nil && *dim < 0 {
+			return "berat dan dimensi tidak boleh negatif"
+		}
+	}
+	if req.Unit == "" {
+		req.Unit = "pcs"
+	}
+	if req.Status == "" {
+		req.Status = "published"
+	}
+	if !productStatuses[req.Status] {
+		return "status harus draft, published, atau archived"
+	}
+	return ""
+}
+
+// CreateProduct inserts a new product.
+func CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var req productRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	now := nowUTC()
+	res, err := db.Exec(
+		`INSERT INTO products (category_id, name, description, barcode, sku, price, stock, low_stock_threshold,
+			is_varians, is_discounted, discount_price, discount_starts_at, discount_ends_at, is_service,
+			is_digital, is_preorder, expected_available_at, weight_grams, length_mm, width_mm, height_mm,
+			unit, status, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		req.CategoryID, req.Name, req.Description, req.Barcode, req.SKU, req.Price, req.Stock,
+		req.LowStockThreshold, req.IsVarians, req.IsDiscounted, req.DiscountPrice, req.DiscountStartsAt,
+		req.DiscountEndsAt, req.IsService, req.IsDigital, req.IsPreorder, req.ExpectedAvailableAt,
+		req.WeightGrams, req.LengthMM, req.WidthMM, req.HeightMM, req.Unit, req.Status, now, now)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "barcode atau sku sudah dipakai produk lain")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	recordAudit(r, "create", "product", id, nil, productSnapshot(id))
+	go indexProductByID(id)
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "produk berhasil dibuat"})
+}
+
+// UpdateProduct overwrites an existing product.
+func UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req productRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	var (
+		wasDiscounted bool
+		oldStock      int
+	)
+	err = db.QueryRow("SELECT is_discounted, stock FROM products WHERE id = ?", id).
+		Scan(&wasDiscounted, &oldStock)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	before := productSnapshot(id)
+	query := `UPDATE products SET category_id = ?, name = ?, description = ?, barcode = ?, sku = ?, price = ?, stock = ?,
+			low_stock_threshold = ?, is_varians = ?, is_discounted = ?, discount_price = ?, discount_starts_at = ?,
+			discount_ends_at = ?, is_service = ?, is_digital = ?, is_preorder = ?, expected_available_at = ?,
+			weight_grams = ?, length_mm = ?, width_mm = ?, height_mm = ?, unit = ?, status = ?, updated_at = ?
+		 WHERE id = ?`
+	args := []any{req.CategoryID, req.Name, req.Description, req.Barcode, req.SKU, req.Price, req.Stock,
+		req.LowStockThreshold, req.IsVarians, req.IsDiscounted, req.DiscountPrice, req.DiscountStartsAt,
+		req.DiscountEndsAt, req.IsService, req.IsDigital, req.IsPreorder, req.ExpectedAvailableAt,
+		req.WeightGrams, req.LengthMM, req.WidthMM, req.HeightMM, req.Unit, req.Status, nowUTC(), id}
+	if req.UpdatedAt != nil {
+		query += " AND updated_at = ?"
+		args = append(args, *req.UpdatedAt)
+	}
+	res, err := db.Exec(query, args...)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "barcode atau sku sudah dipakai produk lain")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 && req.UpdatedAt != nil {
+		respondError(w, http.StatusConflict, "produk sudah diubah oleh admin lain, muat ulang dulu")
+		return
+	}
+	if !wasDiscounted && req.IsDiscounted {
+		notifyWishlistDiscount(id)
+	}
+	if oldStock <= 0 && req.Stock > 0 {
+		notifyPreorderArrival(id)
+	}
+	after := productSnapshot(id)
+	recordAudit(r, "update", "product", id, before, after)
+	recordProductChanges(r, id, nil, before, after)
+	go indexProductByID(id)
+	respondMessage(w, http.StatusOK, "produk berhasil diupdate")
+}
+
+// PublishProduct moves a product to the published state so it shows up in
+// the public catalog; drafts and archived products come back the same way.
+func PublishProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	before := productSnapshot(id)
+	res, err := db.Exec(
+		"UPDATE products SET status = 'published', updated_at = ? WHERE id = ? AND deleted_at IS NULL",
+		nowUTC(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	}
+	after := productSnapshot(id)
+	recordAudit(r, "publish", "product", id, before, after)
+	recordProductChanges(r, id, nil, before, after)
+	go indexProductByID(id)
+	respondMessage(w, http.StatusOK, "produk berhasil dipublish")
+}
+
+// DeleteProduct removes a product and its dependent rows.
+func DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	before := productSnapshot(id)
+	// Soft delete: order_items and cart_items keep valid product references,
+	// and the purge worker hard-deletes unreferenced rows later.
+	ok, err := softDelete("products", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	}
+	recordAudit(r, "delete", "product", id, before, nil)
+	go removeProductFromIndex(id)
+	respondMessage(w, http.StatusOK, "produk berhasil dihapus")
+}
+
+// GetProductVariants lists the variants of one product.
+func GetProductVariants(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	variants, err := fetchProductVariants(id, includeDeleted(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, variants)
+}
+
+// fetchProductVariants loads a product's variants, optionally including
+// soft-deleted rows.
+func fetchProductVariants(productID int64, withDeleted bool) ([]ProductVariant, error) {
+	query := `SELECT id, product_id, name, sku, barcode, price, stock, low_stock_threshold, is_discounted,
+			discount_price, discount_starts_at, discount_ends_at, weight_grams, version
+		 FROM product_variants WHERE product_id = ?`
+	if !withDeleted {
+		query += notDeleted("")
+	}
+	rows, err := db.Query(query, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	variants := []ProductVariant{}
+	for rows.Next() {
+		var (
+			v                    ProductVariant
+			sku, barcode         sql.NullString
+			discStarts, discEnds sql.NullTime
+			weight               sql.NullInt64
+		)
+		if err := rows.Scan(&v.ID, &v.ProductID, &v.Name, &sku, &barcode, &v.Price, &v.Stock,
+			&v.LowStockThreshold, &v.IsDiscounted, &v.DiscountPrice, &discStarts, &discEnds,
+			&weight, &v.Version); err != nil {
+			return nil, err
+		}
+		if sku.Valid {
+			v.SKU = &sku.String
+		}
+		if barcode.Valid {
+			v.Barcode = &barcode.String
+		}
+		if discStarts.Valid {
+			v.DiscountStartsAt = &discStarts.Time
+		}
+		if discEnds.Valid {
+			v.DiscountEndsAt = &discEnds.Time
+		}
+		if weight.Valid {
+			g := int(weight.Int64)
+			v.WeightGrams = &g
+		}
+		applyVariantPricing(&v)
+		variants = append(variants, v)
+	}
+	return variants, nil
+}
+
+// fetchVariant loads one variant row by id, regardless of soft deletion.
+func fetchVariant(id int64) (ProductVariant, error) {
+	var (
+		v                    ProductVariant
+		sku, barcode         sql.NullString
+		discStarts, discEnds sql.NullTime
+		weight               sql.NullInt64
+	)
+	err := db.QueryRow(
+		`SELECT id, product_id, name, sku, barcode, price, stock, low_stock_threshold, is_discounted,
+			discount_price, discount_starts_at, discount_ends_at, weight_grams, version
+		 FROM product_variants WHERE id = ?`, id).
+		Scan(&v.ID, &v.ProductID, &v.Name, &sku, &barcode, &v.Price, &v.Stock,
+			&v.LowStockThreshold, &v.IsDiscounted, &v.DiscountPrice, &discStarts, &discEnds, &weight, &v.Version)
+	if err != nil {
+		return v, err
+	}
+	if sku.Valid {
+		v.SKU = &sku.String
+	}
+	if barcode.Valid {
+		v.Barcode = &barcode.String
+	}
+	if discStarts.Valid {
+		v.DiscountStartsAt = &discStarts.Time
+	}
+	if discEnds.Valid {
+		v.DiscountEndsAt = &discEnds.Time
+	}
+	if weight.Valid {
+		g := int(weight.Int64)
+		v.WeightGrams = &g
+	}
+	applyVariantPricing(&v)
+	return v, nil
+}
+
+type variantRequest struct {
+	Name              string     `json:"name"`
+	SKU               *string    `json:"sku"`
+	Barcode           *string    `json:"barcode"`
+	Price             float64    `json:"price"`
+	Stock             int        `json:"stock"`
+	LowStockThreshold int        `json:"low_stock_threshold"`
+	IsDiscounted      bool       `json:"is_discounted"`
+	DiscountPrice     float64    `json:"discount_price"`
+	DiscountStartsAt  *time.Time `json:"discount_starts_at"`
+	DiscountEndsAt    *time.Time `json:"discount_ends_at"`
+	WeightGrams       *int       `json:"weight_grams"`
+	// Version, when sent, must match the stored row or the update is rejected
+	// with a conflict.
+	Version *int `json:"version"`
+}
+
+// CreateProductVariant adds a variant under a product.
+func CreateProductVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req variantRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Name == "" {
+		respondError(w, http.StatusBadRequest, "nama varian wajib diisi")
+		return
+	}
+	if req.WeightGrams != nil && *req.WeightGrams < 0 {
+		respondError(w, http.StatusBadRequest, "berat tidak boleh negatif")
+		return
+	}
+	if req.LowStockThreshold < 0 {
+		respondError(w, http.StatusBadRequest, "low_stock_threshold tidak boleh negatif")
+		return
+	}
+	if req.DiscountStartsAt != nil && req.DiscountEndsAt != nil && !req.DiscountEndsAt.After(*req.DiscountStartsAt) {
+		respondError(w, http.StatusBadRequest, "discount_ends_at harus setelah discount_starts_at")
+		return
+	}
+	var isVarians bool
+	err = db.QueryRow("SELECT is_varians FROM products WHERE id = ?", productID).Scan(&isVarians)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !isVarians {
+		respondError(w, http.StatusBadRequest, "produk ini tidak memakai varian")
+		return
+	}
+	res, err := db.Exec(
+		`INSERT INTO product_variants (product_id, name, sku, barcode, price, stock, low_stock_threshold,
+			is_discounted, discount_price, discount_starts_at, discount_ends_at, weight_grams)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		productID, req.Name, req.SKU, req.Barcode, req.Price, req.Stock, req.LowStockThreshold,
+		req.IsDiscounted, req.DiscountPrice, req.DiscountStartsAt, req.DiscountEndsAt, req.WeightGrams)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "barcode atau sku sudah dipakai varian lain")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	recordAudit(r, "create", "variant", id, nil, req)
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "varian berhasil dibuat"})
+}
+
+// UpdateProductVariant overwrites an existing variant.
+func UpdateProductVariant(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req variantRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	var (
+		productID     int64
+		wasDiscounted bool
+	)
+	err = db.QueryRow("SELECT product_id, is_discounted FROM product_variants WHERE id = ?",
+		id).Scan(&productID, &wasDiscounted)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "varian tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	before := variantSnapshot(id)
+	query := `UPDATE product_variants SET name = ?, sku = ?, barcode = ?, price = ?, stock = ?, low_stock_threshold = ?,
+			is_discounted = ?, discount_price = ?, discount_starts_at = ?, discount_ends_at = ?,
+			weight_grams = ?, version = version + 1
+		 WHERE id = ?`
+	args := []any{req.Name, req.SKU, req.Barcode, req.Price, req.Stock, req.LowStockThreshold,
+		req.IsDiscounted, req.DiscountPrice, req.DiscountStartsAt, req.DiscountEndsAt, req.WeightGrams, id}
+	if req.Version != nil {
+		query += " AND version = ?"
+		args = append(args, *req.Version)
+	}
+	res, err := db.Exec(query, args...)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "barcode atau sku sudah dipakai varian lain")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 && req.Version != nil {
+		respondError(w, http.StatusConflict, "varian sudah diubah oleh admin lain, muat ulang dulu")
+		return
+	}
+	if !wasDiscounted && req.IsDiscounted {
+		notifyWishlistDiscount(productID)
+	}
+	after := variantSnapshot(id)
+	recordAudit(r, "update", "variant", id, before, after)
+	recordProductChanges(r, productID, &id, before, after)
+	respondMessage(w, http.StatusOK, "varian berhasil diupdate")
+}
+
+// DeleteProductVariant removes a variant.
+func DeleteProductVariant(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	deleted, err := softDelete("product_variants", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, "varian tidak ditemukan")
+		return
+	}
+	recordAudit(r, "delete", "variant", id, nil, nil)
+	respondMessage(w, http.StatusOK, "varian berhasil dihapus")
+}
+
+// GetProductImages lists the media of one product, optionally filtered with
+// ?type=image or ?type=video.
+func GetProductImages(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	images, err := fetchProductImages(id, includeDeleted(r), r.URL.Query().Get("type"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, images)
+}
+
+// fetchProductImages loads a product's media, optionally including
+// soft-deleted rows and filtering by media type.
+func fetchProductImages(productID int64, withDeleted bool, mediaType string) ([]ProductImage, error) {
+	query := `SELECT id, product_id, url, media_type, thumbnail_url, duration_seconds
+		 FROM product_images WHERE product_id = ?`
+	args := []any{productID}
+	if !withDeleted {
+		query += notDeleted("")
+	}
+	if mediaType != "" {
+		query += " AND media_type = ?"
+		args = append(args, mediaType)
+	}
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	images := []ProductImage{}
+	for rows.Next() {
+		var (
+			img      ProductImage
+			thumb    sql.NullString
+			duration sql.NullInt64
+		)
+		if err := rows.Scan(&img.ID, &img.ProductID, &img.URL, &img.MediaType,
+			&thumb, &duration); err != nil {
+			return nil, err
+		}
+		if thumb.Valid {
+			img.ThumbnailURL = &thumb.String
+		}
+		if duration.Valid {
+			d := int(duration.Int64)
+			img.DurationSeconds = &d
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// CreateProductImage attaches a media URL to a product; videos additionally
+// carry a thumbnail and duration.
+func CreateProductImage(w http.ResponseWriter, r *http.Request) {
+	productID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		URL             string  `json:"url"`
+		MediaType       string  `json:"media_type"`
+		ThumbnailURL    *string `json:"thumbnail_url"`
+		DurationSeconds *int    `json:"duration_seconds"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.URL == "" {
+		respondError(w, http.StatusBadRequest, "url wajib diisi")
+		return
+	}
+	if req.MediaType == "" {
+		req.MediaType = "image"
+	}
+	if req.MediaType != "image" && req.MediaType != "video" {
+		respondError(w, http.StatusBadRequest, "media_type harus image atau video")
+		return
+	}
+	if req.MediaType == "video" {
+		if req.ThumbnailURL == nil || *req.ThumbnailURL == "" {
+			respondError(w, http.StatusBadRequest, "video wajib menyertakan thumbnail_url")
+			return
+		}
+		if req.DurationSeconds != nil && *req.DurationSeconds < 0 {
+			respondError(w, http.StatusBadRequest, "duration_seconds tidak boleh negatif")
+			return
+		}
+	}
+	res, err := db.Exec(
+		`INSERT INTO product_images (product_id, url, media_type, thumbnail_url, duration_seconds)
+		 VALUES (?, ?, ?, ?, ?)`,
+		productID, req.URL, req.MediaType, req.ThumbnailURL, req.DurationSeconds)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "media berhasil ditambahkan"})
+}
+
+// DeleteProductImage removes an image.
+func DeleteProductImage(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	deleted, err := softDelete("product_images", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		respondError(w, http.StatusNotFound, "gambar tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "gambar berhasil dihapus")
+}
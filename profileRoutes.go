@@ -0,0 +1,155 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+func registerProfileRoutes(r *Router) {
+	r.GET("/api/v1/me", AuthMiddleware(GetMyProfile))
+	r.PATCH("/api/v1/me", AuthMiddleware(UpdateMyProfile))
+}
+
+// GetMyProfile returns the profile of whichever account the JWT belongs to.
+// Users get their full row; admins and employees only have username and email.
+func GetMyProfile(w http.ResponseWriter, r *http.Request) {
+	claims := currentClaims(r)
+	if claims.Role == "user" {
+		var (
+			u        User
+			verified bool
+		)
+		err := db.QueryRow(
+			"SELECT id, username, email, phone, hearts, is_verified, created_at FROM users WHERE id = ?",
+			claims.ID).Scan(&u.ID, &u.Username, &u.Email, &u.Phone, &u.Hearts, &verified, &u.CreatedAt)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"id":          u.ID,
+			"username":    u.Username,
+			"email":       u.Email,
+			"phone":       u.Phone,
+			"hearts":      u.Hearts,
+			"is_verified": verified,
+			"role":        "user",
+			"created_at":  displayTime(u.CreatedAt),
+		})
+		return
+	}
+	table, ok := accountTable(claims.Role)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "role tidak dikenal")
+		return
+	}
+	var username, email string
+	err := db.QueryRow("SELECT username, email FROM "+table+" WHERE id = ?", claims.ID).
+		Scan(&username, &email)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"id":       claims.ID,
+		"username": username,
+		"email":    email,
+		"role":     claims.Role,
+	})
+}
+
+// UpdateMyProfile changes username, email, or (for users) phone. A new email
+// is checked against every account table and, for users, resets verification
+// so the order flow stays gated until the new address is confirmed.
+func UpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Username *string `json:"username"`
+		Email    *string `json:"email"`
+		Phone    *string `json:"phone"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Username == nil && req.Email == nil && req.Phone == nil {
+		respondError(w, http.StatusBadRequest, "tidak ada field yang diubah")
+		return
+	}
+	claims := currentClaims(r)
+	table, ok := accountTable(claims.Role)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "role tidak dikenal")
+		return
+	}
+	if req.Phone != nil && claims.Role != "user" {
+		respondError(w, http.StatusBadRequest, "hanya akun user yang punya nomor telepon")
+		return
+	}
+	emailChanged := false
+	if req.Email != nil {
+		email := strings.ToLower(strings.TrimSpace(*req.Email))
+		if email == "" {
+			respondError(w, http.StatusBadRequest, "email tidak boleh kosong")
+			return
+		}
+		var current string
+		if err := db.QueryRow("SELECT email FROM "+table+" WHERE id = ?", claims.ID).
+			Scan(&current); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if email != current {
+			taken, err := emailInUse(email)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if taken {
+				respondError(w, http.StatusConflict, "email sudah dipakai akun lain")
+				return
+			}
+			emailChanged = true
+		}
+		*req.Email = email
+	}
+	sets := []string{}
+	args := []any{}
+	if req.Username != nil {
+		if *req.Username == "" {
+			respondError(w, http.StatusBadRequest, "username tidak boleh kosong")
+			return
+		}
+		sets = append(sets, "username = ?")
+		args = append(args, *req.Username)
+	}
+	if req.Email != nil {
+		sets = append(sets, "email = ?")
+		args = append(args, *req.Email)
+		if emailChanged && claims.Role == "user" {
+			sets = append(sets, "is_verified = 0")
+		}
+	}
+	if req.Phone != nil {
+		sets = append(sets, "phone = ?")
+		if *req.Phone == "" {
+			args = append(args, nil)
+		} else {
+			args = append(args, *req.Phone)
+		}
+	}
+	args = append(args, claims.ID)
+	_, err := db.Exec("UPDATE "+table+" SET "+strings.Join(sets, ", ")+" WHERE id = ?", args...)
+	if isDuplicateKeyError(err) {
+		respondError(w, http.StatusConflict, "username atau nomor telepon sudah dipakai")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if emailChanged && claims.Role == "user" {
+		sendVerificationMail(*req.Email)
+		respondMessage(w, http.StatusOK, "profil diperbarui, cek email baru kamu untuk verifikasi ulang")
+		return
+	}
+	respondMessage(w, http.StatusOK, "profil berhasil diperbarui")
+}
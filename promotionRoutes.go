@@ -0,0 +1,294 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+func registerPromotionRoutes(r *Router) {
+	r.GET("/api/v1/admin/promotions", RoleMiddleware("admin")(GetPromotions))
+	r.POST("/api/v1/admin/promotions", RoleMiddleware("admin")(CreatePromotion))
+	r.PUT("/api/v1/admin/promotions/:id", RoleMiddleware("admin")(UpdatePromotion))
+	r.DELETE("/api/v1/admin/promotions/:id", RoleMiddleware("admin")(DeletePromotion))
+}
+
+// Promotion is one configurable promo evaluated against the cart. Supported
+// types: buy_x_get_y (product_id, buy_qty, get_qty — every buy_qty units make
+// get_qty of them free), category_percent (category_id, percent off), and
+// spend_gift (threshold spend adds gift_product_id to the order for free).
+type Promotion struct {
+	ID            int64     `json:"id"`
+	Name          string    `json:"name"`
+	PromoType     string    `json:"promo_type"`
+	ProductID     *int64    `json:"product_id"`
+	CategoryID    *int64    `json:"category_id"`
+	BuyQty        int       `json:"buy_qty"`
+	GetQty        int       `json:"get_qty"`
+	Percent       float64   `json:"percent"`
+	Threshold     float64   `json:"threshold"`
+	GiftProductID *int64    `json:"gift_product_id"`
+	IsActive      bool      `json:"is_active"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+const promotionColumns = "id, name, promo_type, product_id, category_id, buy_qty, get_qty, percent, threshold, gift_product_id, is_active, created_at"
+
+func scanPromotion(row interface{ Scan(...any) error }) (Promotion, error) {
+	var p Promotion
+	err := row.Scan(&p.ID, &p.Name, &p.PromoType, &p.ProductID, &p.CategoryID,
+		&p.BuyQty, &p.GetQty, &p.Percent, &p.Threshold, &p.GiftProductID,
+		&p.IsActive, &p.CreatedAt)
+	return p, err
+}
+
+// promoLine is one cart or order line as the promotions engine sees it.
+type promoLine struct {
+	ProductID int64
+	Quantity  int
+	Price     float64
+}
+
+// AppliedPromotion is one promo that matched the cart, itemized so the
+// client can show where the discount came from.
+type AppliedPromotion struct {
+	PromotionID   int64   `json:"promotion_id"`
+	Name          string  `json:"name"`
+	Discount      float64 `json:"discount"`
+	GiftProductID *int64  `json:"gift_product_id,omitempty"`
+}
+
+// evaluatePromotions runs every active promotion against the cart lines in
+// one pass and returns the promos that applied.
+func evaluatePromotions(lines []promoLine) ([]AppliedPromotion, error) {
+	rows, err := db.Query("SELECT " + promotionColumns + " FROM promotions WHERE is_active = 1")
+	if err != nil {
+		return nil, err
+	}
+	var promos []Promotion
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		promos = append(promos, p)
+	}
+	rows.Close()
+	if len(promos) == 0 {
+		return []AppliedPromotion{}, nil
+	}
+
+	var total float64
+	for _, l := range lines {
+		total += l.Price * float64(l.Quantity)
+	}
+	var categories map[int64]int64
+	applied := []AppliedPromotion{}
+	for _, p := range promos {
+		switch p.PromoType {
+		case "buy_x_get_y":
+			if p.ProductID == nil || p.BuyQty <= 0 || p.GetQty <= 0 {
+				continue
+			}
+			for _, l := range lines {
+				if l.ProductID != *p.ProductID {
+					continue
+				}
+				freeUnits := l.Quantity / (p.BuyQty + p.GetQty) * p.GetQty
+				if freeUnits > 0 {
+					applied = append(applied, AppliedPromotion{
+						PromotionID: p.ID,
+						Name:        p.Name,
+						Discount:    l.Price * float64(freeUnits),
+					})
+				}
+			}
+		case "category_percent":
+			if p.CategoryID == nil || p.Percent <= 0 {
+				continue
+			}
+			if categories == nil {
+				categories = map[int64]int64{}
+				for _, l := range lines {
+					var categoryID int64
+					if err := db.QueryRow("SELECT category_id FROM products WHERE id = ?",
+						l.ProductID).Scan(&categoryID); err != nil {
+						return nil, err
+					}
+					categories[l.ProductID] = categoryID
+				}
+			}
+			var base float64
+			for _, l := range lines {
+				if categories[l.ProductID] == *p.CategoryID {
+					base += l.Price * float64(l.Quantity)
+				}
+			}
+			if base > 0 {
+				applied = append(applied, AppliedPromotion{
+					PromotionID: p.ID,
+					Name:        p.Name,
+					Discount:    base * p.Percent / 100,
+				})
+			}
+		case "spend_gift":
+			if p.GiftProductID == nil || p.Threshold <= 0 {
+				continue
+			}
+			if total >= p.Threshold {
+				applied = append(applied, AppliedPromotion{
+					PromotionID:   p.ID,
+					Name:          p.Name,
+					GiftProductID: p.GiftProductID,
+				})
+			}
+		}
+	}
+	return applied, nil
+}
+
+// recordOrderPromotions persists the applied promotions on an order, inside
+// the caller's transaction.
+func recordOrderPromotions(q dbtx, orderID int64, applied []AppliedPromotion) error {
+	for _, a := range applied {
+		if _, err := q.Exec(
+			"INSERT INTO order_promotions (order_id, promotion_id, name, discount, gift_product_id) VALUES (?, ?, ?, ?, ?)",
+			orderID, a.PromotionID, a.Name, a.Discount, a.GiftProductID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPromotions lists every promotion.
+func GetPromotions(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT " + promotionColumns + " FROM promotions")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	promos := []Promotion{}
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		promos = append(promos, p)
+	}
+	respondJSON(w, http.StatusOK, promos)
+}
+
+type promotionRequest struct {
+	Name          string  `json:"name"`
+	PromoType     string  `json:"promo_type"`
+	ProductID     *int64  `json:"product_id"`
+	CategoryID    *int64  `json:"category_id"`
+	BuyQty        int     `json:"buy_qty"`
+	GetQty        int     `json:"get_qty"`
+	Percent       float64 `json:"percent"`
+	Threshold     float64 `json:"threshold"`
+	GiftProductID *int64  `json:"gift_product_id"`
+	IsActive      bool    `json:"is_active"`
+}
+
+func (req *promotionRequest) validate() string {
+	if req.Name == "" {
+		return "nama promo wajib diisi"
+	}
+	switch req.PromoType {
+	case "buy_x_get_y":
+		if req.ProductID == nil || req.BuyQty <= 0 || req.GetQty <= 0 {
+			return "buy_x_get_y butuh product_id, buy_qty, dan get_qty"
+		}
+	case "category_percent":
+		if req.CategoryID == nil || req.Percent <= 0 || req.Percent > 100 {
+			return "category_percent butuh category_id dan percent antara 0 dan 100"
+		}
+	case "spend_gift":
+		if req.GiftProductID == nil || req.Threshold <= 0 {
+			return "spend_gift butuh gift_product_id dan threshold"
+		}
+	default:
+		return "promo_type tidak dikenal"
+	}
+	return ""
+}
+
+// CreatePromotion inserts a promotion.
+func CreatePromotion(w http.ResponseWriter, r *http.Request) {
+	var req promotionRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	res, err := db.Exec(
+		`INSERT INTO promotions (name, promo_type, product_id, category_id, buy_qty, get_qty,
+			percent, threshold, gift_product_id, is_active, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		req.Name, req.PromoType, req.ProductID, req.CategoryID, req.BuyQty, req.GetQty,
+		req.Percent, req.Threshold, req.GiftProductID, req.IsActive, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "promo berhasil dibuat"})
+}
+
+// UpdatePromotion overwrites an existing promotion.
+func UpdatePromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req promotionRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if msg := req.validate(); msg != "" {
+		respondError(w, http.StatusBadRequest, msg)
+		return
+	}
+	res, err := db.Exec(
+		`UPDATE promotions SET name = ?, promo_type = ?, product_id = ?, category_id = ?, buy_qty = ?,
+			get_qty = ?, percent = ?, threshold = ?, gift_product_id = ?, is_active = ?
+		 WHERE id = ?`,
+		req.Name, req.PromoType, req.ProductID, req.CategoryID, req.BuyQty, req.GetQty,
+		req.Percent, req.Threshold, req.GiftProductID, req.IsActive, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "promo tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "promo berhasil diupdate")
+}
+
+// DeletePromotion removes a promotion.
+func DeletePromotion(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("DELETE FROM promotions WHERE id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "promo tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "promo berhasil dihapus")
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ipThrottle is an in-memory sliding-window rate limiter keyed by source IP.
+// It is deliberately process-local, same as the suggestion cache: one instance
+// is the deployment target for this service.
+type ipThrottle struct {
+	mu       sync.Mutex
+	attempts map[string][]time.Time
+	limit    int
+	window   time.Duration
+}
+
+func newIPThrottle(limit int, window time.Duration) *ipThrottle {
+	return &ipThrottle{
+		attempts: map[string][]time.Time{},
+		limit:    limit,
+		window:   window,
+	}
+}
+
+// allow records one attempt from ip and reports whether it is still within
+// the window's budget. A burst right past the limit is logged once so it
+// shows up when reviewing suspicious traffic.
+func (t *ipThrottle) allow(ip string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	kept := t.attempts[ip][:0]
+	for _, at := range t.attempts[ip] {
+		if now.Sub(at) <= t.window {
+			kept = append(kept, at)
+		}
+	}
+	kept = append(kept, now)
+	t.attempts[ip] = kept
+	if len(kept) == t.limit+1 {
+		log.Printf("percobaan login/register dari IP %s melewati batas (%d dalam %s)",
+			ip, t.limit, t.window)
+	}
+	return len(kept) <= t.limit
+}
+
+// authThrottle guards login and register: 10 attempts per IP per minute.
+var authThrottle = newIPThrottle(10, time.Minute)
+
+// clientIP extracts the caller's IP, trusting X-Forwarded-For when a proxy
+// sets it.
+func clientIP(r *http.Request) string {
+	if fwd := r.Header.Get("X-Forwarded-For"); fwd != "" {
+		if first, _, ok := strings.Cut(fwd, ","); ok {
+			return strings.TrimSpace(first)
+		}
+		return strings.TrimSpace(fwd)
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// requireAuthAttempt writes a 429 and returns false when the caller's IP has
+// burned through its attempt budget.
+func requireAuthAttempt(w http.ResponseWriter, r *http.Request) bool {
+	if !authThrottle.allow(clientIP(r)) {
+		respondError(w, http.StatusTooManyRequests, "terlalu banyak percobaan, coba lagi nanti")
+		return false
+	}
+	return true
+}
+
+const loginThrottleLimit = 5
+
+// requireLoginAttempt throttles login per email+IP. With Redis configured the
+// counter is shared across replicas and the penalty doubles with every
+// attempt past the limit; without Redis it falls back to the local IP
+// throttle. Redis outages also fall back rather than blocking logins.
+func requireLoginAttempt(w http.ResponseWriter, r *http.Request, email string) bool {
+	if redisConn == nil {
+		return requireAuthAttempt(w, r)
+	}
+	key := "login:" + strings.ToLower(email) + ":" + clientIP(r)
+	count, err := redisConn.incr(key)
+	if err != nil {
+		log.Printf("redis throttle tidak tersedia, pakai limiter lokal: %v", err)
+		return requireAuthAttempt(w, r)
+	}
+	if count == 1 {
+		if err := redisConn.expire(key, 60); err != nil {
+			log.Printf("gagal set expire throttle login: %v", err)
+		}
+	}
+	if count > loginThrottleLimit {
+		backoff := 1 << min(int(count)-loginThrottleLimit, 8) // 2s, 4s, ... capped at 256s
+		redisConn.expire(key, backoff)
+		w.Header().Set("Retry-After", strconv.Itoa(backoff))
+		respondError(w, http.StatusTooManyRequests, "terlalu banyak percobaan login, coba lagi nanti")
+		return false
+	}
+	return true
+}
+
+// clearLoginThrottle resets the counter after a successful login.
+func clearLoginThrottle(r *http.Request, email string) {
+	if redisConn == nil {
+		return
+	}
+	if err := redisConn.del("login:" + strings.ToLower(email) + ":" + clientIP(r)); err != nil {
+		log.Printf("gagal reset throttle login: %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func registerRbacRoutes(r *Router) {
+	r.GET("/api/v1/admin/permissions", RoleMiddleware("admin")(GetPermissions))
+	r.GET("/api/v1/admin/roles/:role/permissions", RoleMiddleware("admin")(GetRolePermissions))
+	r.PUT("/api/v1/admin/roles/:role/permissions", RoleMiddleware("admin")(SetRolePermissions))
+}
+
+// PermissionMiddleware restricts a route to roles granted the named
+// permission in role_permissions. The admin role always passes, so a botched
+// permission edit can never lock admins out of the endpoints needed to fix it.
+func PermissionMiddleware(permission string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return AuthMiddleware(func(w http.ResponseWriter, r *http.Request) {
+			claims := currentClaims(r)
+			if claims.Role == "admin" {
+				next(w, r)
+				return
+			}
+			allowed, err := roleHasPermission(claims.Role, permission)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			if !allowed {
+				respondError(w, http.StatusForbidden, "role ini tidak punya izin "+permission)
+				return
+			}
+			next(w, r)
+		})
+	}
+}
+
+func roleHasPermission(role, permission string) (bool, error) {
+	var n int
+	err := db.QueryRow(
+		`SELECT COUNT(*) FROM role_permissions rp
+		 JOIN roles r ON r.id = rp.role_id
+		 JOIN permissions p ON p.id = rp.permission_id
+		 WHERE r.name = ? AND p.name = ?`, role, permission).Scan(&n)
+	return n > 0, err
+}
+
+// GetPermissions lists every known permission.
+func GetPermissions(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT name FROM permissions ORDER BY name")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	permissions := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		permissions = append(permissions, name)
+	}
+	respondJSON(w, http.StatusOK, permissions)
+}
+
+// GetRolePermissions lists the permissions granted to one role.
+func GetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	role := Param(r, "role")
+	var roleID int64
+	err := db.QueryRow("SELECT id FROM roles WHERE name = ?", role).Scan(&roleID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "role tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rows, err := db.Query(
+		`SELECT p.name FROM role_permissions rp
+		 JOIN permissions p ON p.id = rp.permission_id
+		 WHERE rp.role_id = ? ORDER BY p.name`, roleID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	permissions := []string{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		permissions = append(permissions, name)
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"role": role, "permissions": permissions})
+}
+
+// SetRolePermissions replaces a role's grants with the posted list.
+func SetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	role := Param(r, "role")
+	var req struct {
+		Permissions []string `json:"permissions"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	var roleID int64
+	err := db.QueryRow("SELECT id FROM roles WHERE name = ?", role).Scan(&roleID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "role tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	err = WithTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM role_permissions WHERE role_id = ?", roleID); err != nil {
+			return err
+		}
+		for _, name := range req.Permissions {
+			res, err := tx.Exec(
+				"INSERT INTO role_permissions (role_id, permission_id) SELECT ?, id FROM permissions WHERE name = ?",
+				roleID, name)
+			if err != nil {
+				return err
+			}
+			if n, _ := res.RowsAffected(); n == 0 {
+				return errUnknownPermission{name}
+			}
+		}
+		return nil
+	})
+	if unknown, ok := err.(errUnknownPermission); ok {
+		respondError(w, http.StatusBadRequest, "permission tidak dikenal: "+unknown.name)
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	recordAudit(r, "update", "role", roleID, nil, req)
+	respondMessage(w, http.StatusOK, "permission role berhasil disimpan")
+}
+
+type errUnknownPermission struct{ name string }
+
+func (e errUnknownPermission) Error() string { return "permission tidak dikenal: " + e.name }
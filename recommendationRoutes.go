@@ -0,0 +1,158 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerRecommendationRoutes(r *Router) {
+	r.GET("/api/v1/users/me/recommendations", AuthMiddleware(MyRecommendations))
+	r.GET("/api/v1/products/:id/recommendations", GetProductRecommendations)
+	r.GET("/api/v1/products/:id/related", GetRelatedProducts)
+	r.POST("/api/v1/admin/recommendations/recompute", RoleMiddleware("admin")(RecomputeRecommendationsNow))
+}
+
+// StartRecommendationWorker recomputes "customers also bought" pairs and the
+// related-products cache nightly.
+func StartRecommendationWorker() {
+	go func() {
+		computeRecommendations()
+		computeRelatedProducts()
+		for range time.Tick(24 * time.Hour) {
+			computeRecommendations()
+			computeRelatedProducts()
+		}
+	}()
+}
+
+// computeRelatedProducts rebuilds the related_products cache. A pair scores
+// three per order the two products appear in together, plus one for sharing
+// a category, so co-purchase signal dominates but new products without sales
+// still get same-category neighbours.
+func computeRelatedProducts() {
+	if _, err := db.Exec("DELETE FROM related_products"); err != nil {
+		log.Printf("gagal bersihkan tabel produk terkait: %v", err)
+		return
+	}
+	_, err := db.Exec(
+		`INSERT INTO related_products (product_id, related_product_id, score, computed_at)
+		 SELECT t.product_id, t.related_id, SUM(t.score), ?
+		 FROM (
+		     SELECT a.product_id AS product_id, b.product_id AS related_id, COUNT(*) * 3 AS score
+		     FROM order_items a
+		     JOIN order_items b ON a.order_id = b.order_id AND a.product_id <> b.product_id
+		     WHERE a.product_id IS NOT NULL AND b.product_id IS NOT NULL
+		     GROUP BY a.product_id, b.product_id
+		     UNION ALL
+		     SELECT p.id, q.id, 1
+		     FROM products p
+		     JOIN products q ON q.category_id = p.category_id AND q.id <> p.id
+		     WHERE p.deleted_at IS NULL AND q.deleted_at IS NULL
+		 ) t
+		 GROUP BY t.product_id, t.related_id`, nowUTC())
+	if err != nil {
+		log.Printf("gagal hitung produk terkait: %v", err)
+		return
+	}
+	log.Print("tabel produk terkait berhasil dihitung ulang")
+}
+
+// computeRecommendations rebuilds product_recommendations from order_items
+// co-occurrence: two products bought in the same order reinforce each other.
+func computeRecommendations() {
+	if _, err := db.Exec("DELETE FROM product_recommendations"); err != nil {
+		log.Printf("gagal bersihkan tabel rekomendasi: %v", err)
+		return
+	}
+	_, err := db.Exec(
+		`INSERT INTO product_recommendations (product_id, recommended_product_id, score, computed_at)
+		 SELECT a.product_id, b.product_id, COUNT(*), ?
+		 FROM order_items a
+		 JOIN order_items b ON a.order_id = b.order_id AND a.product_id <> b.product_id
+		 WHERE a.product_id IS NOT NULL AND b.product_id IS NOT NULL
+		 GROUP BY a.product_id, b.product_id`, nowUTC())
+	if err != nil {
+		log.Printf("gagal hitung rekomendasi: %v", err)
+		return
+	}
+	log.Print("tabel rekomendasi berhasil dihitung ulang")
+}
+
+// RecomputeRecommendationsNow lets admins trigger the nightly job manually.
+func RecomputeRecommendationsNow(w http.ResponseWriter, r *http.Request) {
+	computeRecommendations()
+	respondMessage(w, http.StatusOK, "rekomendasi berhasil dihitung ulang")
+}
+
+func respondRecommendedProducts(w http.ResponseWriter, query string, args ...any) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	products := []Product{}
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		products = append(products, p)
+	}
+	respondJSON(w, http.StatusOK, products)
+}
+
+// GetProductRecommendations lists "customers also bought" for one product.
+func GetProductRecommendations(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	respondRecommendedProducts(w,
+		`SELECT `+prefixedProductColumns("p")+`
+		 FROM product_recommendations pr
+		 JOIN products p ON p.id = pr.recommended_product_id
+		 WHERE pr.product_id = ?
+		 ORDER BY pr.score DESC
+		 LIMIT 10`, id)
+}
+
+// GetRelatedProducts lists the cached related products for one product,
+// same-category plus co-purchase, strongest first.
+func GetRelatedProducts(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	respondRecommendedProducts(w,
+		`SELECT `+prefixedProductColumns("p")+`
+		 FROM related_products rp
+		 JOIN products p ON p.id = rp.related_product_id
+		 WHERE rp.product_id = ? AND p.deleted_at IS NULL
+		 ORDER BY rp.score DESC
+		 LIMIT 10`, id)
+}
+
+// MyRecommendations suggests products based on what the user already bought.
+func MyRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	respondRecommendedProducts(w,
+		`SELECT `+prefixedProductColumns("p")+`
+		 FROM product_recommendations pr
+		 JOIN products p ON p.id = pr.recommended_product_id
+		 WHERE pr.product_id IN (
+			SELECT DISTINCT oi.product_id FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE o.user_id = ? AND o.status IN ('paid', 'delivered') AND oi.product_id IS NOT NULL)
+		 AND pr.recommended_product_id NOT IN (
+			SELECT DISTINCT oi.product_id FROM order_items oi
+			JOIN orders o ON o.id = oi.order_id
+			WHERE o.user_id = ? AND oi.product_id IS NOT NULL)
+		 GROUP BY p.id
+		 ORDER BY MAX(pr.score) DESC
+		 LIMIT 10`, userID, userID)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Minimal Redis client speaking just enough RESP for the throttle commands
+// (INCR/EXPIRE/TTL/DEL). Hand-rolled like the other integrations so the
+// service stays dependency-free; connections are pooled on a small channel.
+
+type redisClient struct {
+	addr string
+	pool chan net.Conn
+}
+
+var redisConn *redisClient
+
+func initRedis() {
+	if config.RedisAddr == "" {
+		return
+	}
+	redisConn = &redisClient{
+		addr: config.RedisAddr,
+		pool: make(chan net.Conn, 4),
+	}
+}
+
+func (c *redisClient) get() (net.Conn, error) {
+	select {
+	case conn := <-c.pool:
+		return conn, nil
+	default:
+		return net.DialTimeout("tcp", c.addr, 2*time.Second)
+	}
+}
+
+func (c *redisClient) put(conn net.Conn) {
+	select {
+	case c.pool <- conn:
+	default:
+		conn.Close()
+	}
+}
+
+// do sends one command and returns the reply as a string; integer replies
+// come back in decimal form.
+func (c *redisClient) do(args ...string) (string, error) {
+	conn, err := c.get()
+	if err != nil {
+		return "", err
+	}
+	conn.SetDeadline(time.Now().Add(2 * time.Second))
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := conn.Write([]byte(sb.String())); err != nil {
+		conn.Close()
+		return "", err
+	}
+	reply, err := readRESP(bufio.NewReader(conn))
+	if err != nil {
+		conn.Close()
+		return "", err
+	}
+	c.put(conn)
+	return reply, nil
+}
+
+func readRESP(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("reply redis kosong")
+	}
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", fmt.Errorf("redis error: %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return "", err
+		}
+		buf := make([]byte, n+2)
+		if _, err := ioReadFull(r, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:n]), nil
+	}
+	return "", fmt.Errorf("tipe reply redis tidak dikenal: %q", line[0])
+}
+
+func ioReadFull(r *bufio.Reader, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		if err != nil {
+			return total, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (c *redisClient) incr(key string) (int64, error) {
+	reply, err := c.do("INCR", key)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(reply, 10, 64)
+}
+
+func (c *redisClient) expire(key string, seconds int) error {
+	_, err := c.do("EXPIRE", key, strconv.Itoa(seconds))
+	return err
+}
+
+func (c *redisClient) del(key string) error {
+	_, err := c.do("DEL", key)
+	return err
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+func registerReferralRoutes(r *Router) {
+	r.GET("/api/v1/me/referral", RoleMiddleware("user")(GetMyReferral))
+}
+
+// myReferralCode returns the user's referral code, minting one on first use.
+func myReferralCode(userID int64) (string, error) {
+	var code sql.NullString
+	if err := db.QueryRow("SELECT referral_code FROM users WHERE id = ?", userID).
+		Scan(&code); err != nil {
+		return "", err
+	}
+	if code.Valid && code.String != "" {
+		return code.String, nil
+	}
+	for i := 0; i < 3; i++ {
+		raw := make([]byte, 4)
+		if _, err := rand.Read(raw); err != nil {
+			return "", err
+		}
+		candidate := strings.ToUpper(hex.EncodeToString(raw))
+		_, err := db.Exec("UPDATE users SET referral_code = ? WHERE id = ?", candidate, userID)
+		if isDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return candidate, nil
+	}
+	return "", fmt.Errorf("gagal membuat kode referral unik")
+}
+
+// linkReferral records that a new account signed up with someone's code. The
+// reward waits until the referee's first paid order so throwaway signups earn
+// nothing. Failures are logged; registration must not break over a bad code.
+func linkReferral(refereeID int64, code string) {
+	var referrerID int64
+	err := db.QueryRow("SELECT id FROM users WHERE referral_code = ?",
+		strings.ToUpper(strings.TrimSpace(code))).Scan(&referrerID)
+	if err == sql.ErrNoRows {
+		log.Printf("kode referral %q tidak dikenal, diabaikan", code)
+		return
+	} else if err != nil {
+		log.Printf("gagal cek kode referral: %v", err)
+		return
+	}
+	if _, err := db.Exec(
+		"INSERT INTO referrals (referrer_id, referee_id, status, created_at) VALUES (?, ?, 'pending', ?)",
+		referrerID, refereeID, nowUTC()); err != nil {
+		log.Printf("gagal mencatat referral user %d: %v", refereeID, err)
+	}
+}
+
+// maybeRewardReferral pays out a pending referral once the referee completes
+// their first paid order: both sides get REFERRAL_REWARD_POINTS points.
+// Called from payment finalization, so failures are logged only.
+func maybeRewardReferral(refereeID int64) {
+	if config.ReferralRewardPoints <= 0 {
+		return
+	}
+	var (
+		referralID, referrerID int64
+	)
+	err := db.QueryRow(
+		"SELECT id, referrer_id FROM referrals WHERE referee_id = ? AND status = 'pending'",
+		refereeID).Scan(&referralID, &referrerID)
+	if err == sql.ErrNoRows {
+		return
+	} else if err != nil {
+		log.Printf("gagal cek referral user %d: %v", refereeID, err)
+		return
+	}
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"UPDATE referrals SET status = 'rewarded', rewarded_at = ? WHERE id = ? AND status = 'pending'",
+			nowUTC(), referralID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			// Another payment confirmation got here first.
+			return sql.ErrNoRows
+		}
+		for _, userID := range []int64{referrerID, refereeID} {
+			if _, err := adjustPoints(tx, userID, config.ReferralRewardPoints,
+				"referral_reward", referralID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		return
+	} else if err != nil {
+		log.Printf("gagal reward referral %d: %v", referralID, err)
+		return
+	}
+	msg := fmt.Sprintf("Kamu mendapat %d points dari program referral.", config.ReferralRewardPoints)
+	createNotification(referrerID, "Reward referral", msg)
+	createNotification(refereeID, "Reward referral", msg)
+}
+
+// GetMyReferral returns the caller's code plus how their referrals are doing.
+func GetMyReferral(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	code, err := myReferralCode(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rows, err := db.Query(
+		`SELECT r.status, r.created_at, u.username FROM referrals r
+		 JOIN users u ON u.id = r.referee_id
+		 WHERE r.referrer_id = ? ORDER BY r.id DESC`, userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	type referralEntry struct {
+		Username  string `json:"username"`
+		Status    string `json:"status"`
+		CreatedAt string `json:"created_at"`
+	}
+	referrals := []referralEntry{}
+	for rows.Next() {
+		var (
+			e       referralEntry
+			created time.Time
+		)
+		if err := rows.Scan(&e.Status, &created, &e.Username); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		e.CreatedAt = displayTime(created)
+		referrals = append(referrals, e)
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"code":      code,
+		"referrals": referrals,
+	})
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"net/http"
+	"time"
+)
+
+func registerRefreshTokenRoutes(r *Router) {
+	r.POST("/api/v1/auth/refresh", RefreshAccessToken)
+}
+
+// refreshTokenTTL returns how long a refresh token lives: the configured
+// default, or the longer remember-me window when the login asked for it.
+func refreshTokenTTL(remember bool) time.Duration {
+	days := config.RefreshTokenDays
+	if remember {
+		days = config.RememberMeDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// issueRefreshToken mints an opaque refresh token for an account and stores
+// only its SHA-256 hash, so a database leak does not leak usable tokens. The
+// caller's IP and user agent are kept alongside so the row doubles as a
+// session entry.
+func issueRefreshToken(accountID int64, role string, r *http.Request, remember bool) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(raw)
+	_, err := db.Exec(
+		"INSERT INTO refresh_tokens (account_id, role, token_hash, ip, user_agent, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		accountID, role, hashRefreshToken(token), clientIP(r), r.UserAgent(),
+		nowUTC().Add(refreshTokenTTL(remember)), nowUTC())
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RefreshAccessToken exchanges a valid refresh token for a fresh access token
+// and a new refresh token. The presented token is revoked in the same
+// transaction (rotation), so each refresh token works exactly once and a
+// replayed token fails loudly.
+func RefreshAccessToken(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.RefreshToken == "" {
+		respondError(w, http.StatusBadRequest, "refresh_token wajib diisi")
+		return
+	}
+	var (
+		accountID int64
+		role      string
+	)
+	err := WithTx(func(tx *sql.Tx) error {
+		err := tx.QueryRow(
+			`SELECT account_id, role FROM refresh_tokens
+			 WHERE token_hash = ? AND revoked_at IS NULL AND expires_at > ?`,
+			hashRefreshToken(req.RefreshToken), nowUTC()).Scan(&accountID, &role)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec("UPDATE refresh_tokens SET revoked_at = ? WHERE token_hash = ?",
+			nowUTC(), hashRefreshToken(req.RefreshToken))
+		return err
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusUnauthorized, "refresh token tidak valid atau sudah dipakai")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondWithToken(w, r, accountID, role, false)
+}
+
+// revokeRefreshTokens invalidates every active refresh token of an account,
+// e.g. after a logout-everywhere or a password change.
+func revokeRefreshTokens(accountID int64, role string) error {
+	_, err := db.Exec(
+		"UPDATE refresh_tokens SET revoked_at = ? WHERE account_id = ? AND role = ? AND revoked_at IS NULL",
+		nowUTC(), accountID, role)
+	return err
+}
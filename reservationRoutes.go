@@ -0,0 +1,205 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func registerReservationRoutes(r *Router) {
+	r.POST("/api/v1/reservations", AuthMiddleware(CreateStockReservation))
+	r.GET("/api/v1/reservations", AuthMiddleware(MyStockReservations))
+	r.GET("/api/v1/reservations/:id", OwnershipMiddleware("reservation")(GetStockReservationDetail))
+	r.GET("/api/v1/admin/reservations/stats", RoleMiddleware("admin")(GetReservationStats))
+}
+
+// GetReservationStats aggregates how reservations behave — how many are
+// active, what is held per product, how fast holds convert or expire — so the
+// hearts/duration policy can be tuned with data instead of guesses.
+func GetReservationStats(w http.ResponseWriter, r *http.Request) {
+	var active int
+	if err := db.QueryRow(
+		"SELECT COUNT(*) FROM stock_reservations WHERE status = 'active'").Scan(&active); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	rows, err := db.Query(
+		`SELECT product_id, SUM(quantity) FROM stock_reservations
+		 WHERE status = 'active' GROUP BY product_id ORDER BY SUM(quantity) DESC`)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	type productHold struct {
+		ProductID int64 `json:"product_id"`
+		Units     int   `json:"units"`
+	}
+	perProduct := []productHold{}
+	for rows.Next() {
+		var ph productHold
+		if err := rows.Scan(&ph.ProductID, &ph.Units); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		perProduct = append(perProduct, ph)
+	}
+	// status_changed_at is stamped on every transition, so the averages only
+	// cover rows created after that column landed.
+	var (
+		avgToPurchase sql.NullFloat64
+		avgToExpire   sql.NullFloat64
+		purchased     sql.NullInt64
+		expired       sql.NullInt64
+	)
+	if err := db.QueryRow(
+		`SELECT
+			AVG(CASE WHEN status = 'purchased' THEN TIMESTAMPDIFF(SECOND, created_at, status_changed_at) END),
+			AVG(CASE WHEN status = 'expired' THEN TIMESTAMPDIFF(SECOND, created_at, status_changed_at) END),
+			SUM(status = 'purchased'),
+			SUM(status = 'expired')
+		 FROM stock_reservations WHERE status IN ('purchased', 'expired')`).
+		Scan(&avgToPurchase, &avgToExpire, &purchased, &expired); err != nil && err != sql.ErrNoRows {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	expiryRate := 0.0
+	if finished := purchased.Int64 + expired.Int64; finished > 0 {
+		expiryRate = float64(expired.Int64) / float64(finished)
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"active_count":               active,
+		"reserved_units_per_product": perProduct,
+		"avg_seconds_to_purchase":    avgToPurchase.Float64,
+		"avg_seconds_to_expire":      avgToExpire.Float64,
+		"purchased_count":            purchased.Int64,
+		"expired_count":              expired.Int64,
+		"expiry_rate":                expiryRate,
+	})
+}
+
+type reservationRequest struct {
+	ProductID int64  `json:"product_id"`
+	VariantID *int64 `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// CreateStockReservation holds stock for the user without an order, using the
+// same hearts-based duration as checkout.
+func CreateStockReservation(w http.ResponseWriter, r *http.Request) {
+	var req reservationRequest
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Quantity <= 0 {
+		respondError(w, http.StatusBadRequest, "quantity minimal 1")
+		return
+	}
+	userID := currentUserID(r)
+	var hearts int
+	if err := db.QueryRow("SELECT hearts FROM users WHERE id = ?", userID).Scan(&hearts); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	duration, ok := reservationDuration(hearts)
+	if !ok {
+		respondError(w, http.StatusForbidden, "hearts habis, tidak bisa reservasi stock")
+		return
+	}
+
+	_, stock, _, err := resolveItemPrice(req.ProductID, req.VariantID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk atau varian tidak ditemukan")
+		return
+	} else if err == errVariantRequired {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if stock < req.Quantity {
+		respondError(w, http.StatusBadRequest, "stock tidak mencukupi")
+		return
+	}
+	err = stockSvc.Reserve(db, req.ProductID, req.VariantID, req.Quantity)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	expiresAt := nowUTC().Add(duration)
+	res, err := db.Exec(
+		`INSERT INTO stock_reservations (user_id, product_id, variant_id, quantity, status, expires_at, created_at)
+		 VALUES (?, ?, ?, ?, 'active', ?, ?)`,
+		userID, req.ProductID, req.VariantID, req.Quantity, expiresAt, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"id":         id,
+		"expires_at": expiresAt,
+	})
+}
+
+const reservationColumns = "id, user_id, order_id, product_id, variant_id, quantity, status, expires_at, created_at"
+
+func scanReservation(row interface{ Scan(...any) error }) (StockReservation, error) {
+	var (
+		sr        StockReservation
+		orderID   sql.NullInt64
+		variantID sql.NullInt64
+	)
+	err := row.Scan(&sr.ID, &sr.UserID, &orderID, &sr.ProductID, &variantID,
+		&sr.Quantity, &sr.Status, &sr.ExpiresAt, &sr.CreatedAt)
+	if orderID.Valid {
+		sr.OrderID = &orderID.Int64
+	}
+	if variantID.Valid {
+		sr.VariantID = &variantID.Int64
+	}
+	return sr, err
+}
+
+// MyStockReservations lists the authenticated user's reservations.
+func MyStockReservations(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		"SELECT "+reservationColumns+" FROM stock_reservations WHERE user_id = ? ORDER BY created_at DESC",
+		currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	reservations := []StockReservation{}
+	for rows.Next() {
+		sr, err := scanReservation(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		reservations = append(reservations, sr)
+	}
+	respondJSON(w, http.StatusOK, reservations)
+}
+
+// GetStockReservationDetail returns one reservation by id; ownership is
+// enforced by OwnershipMiddleware on the route.
+func GetStockReservationDetail(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	sr, err := scanReservation(db.QueryRow(
+		"SELECT "+reservationColumns+" FROM stock_reservations WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "reservasi tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, sr)
+}
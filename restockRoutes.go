@@ -0,0 +1,123 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerRestockRoutes(r *Router) {
+	r.POST("/api/v1/restock-requests", AuthMiddleware(CreateRestockRequest))
+	r.GET("/api/v1/restock-requests", RoleMiddleware("admin", "stocker")(GetRestockRequests))
+	r.GET("/api/v1/admin/reports/restock-demand", RoleMiddleware("admin")(GetRestockDemandReport))
+}
+
+// GetRestockDemandReport aggregates restock requests per product and variant
+// with current stock, so purchasing knows what to order first.
+func GetRestockDemandReport(w http.ResponseWriter, r *http.Request) {
+	rows, err := readOnlyDB().Query(
+		`SELECT rr.product_id, rr.variant_id, p.name, v.name,
+			COUNT(*), MIN(rr.created_at), MAX(rr.created_at), COALESCE(v.stock, p.stock)
+		 FROM restock_requests rr
+		 JOIN products p ON p.id = rr.product_id
+		 LEFT JOIN product_variants v ON v.id = rr.variant_id
+		 GROUP BY rr.product_id, rr.variant_id, p.name, v.name, COALESCE(v.stock, p.stock)
+		 ORDER BY COUNT(*) DESC`)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	type demandRow struct {
+		ProductID    int64   `json:"product_id"`
+		VariantID    *int64  `json:"variant_id"`
+		ProductName  string  `json:"product_name"`
+		VariantName  *string `json:"variant_name"`
+		Requests     int     `json:"requests"`
+		FirstRequest string  `json:"first_request"`
+		LastRequest  string  `json:"last_request"`
+		CurrentStock int     `json:"current_stock"`
+	}
+	report := []demandRow{}
+	for rows.Next() {
+		var (
+			d           demandRow
+			variantID   sql.NullInt64
+			variantName sql.NullString
+			first, last time.Time
+		)
+		if err := rows.Scan(&d.ProductID, &variantID, &d.ProductName, &variantName,
+			&d.Requests, &first, &last, &d.CurrentStock); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if variantID.Valid {
+			d.VariantID = &variantID.Int64
+		}
+		if variantName.Valid {
+			d.VariantName = &variantName.String
+		}
+		d.FirstRequest = displayTime(first)
+		d.LastRequest = displayTime(last)
+		report = append(report, d)
+	}
+	respondJSON(w, http.StatusOK, report)
+}
+
+// CreateRestockRequest lets a user ask for an out-of-stock product to be restocked.
+func CreateRestockRequest(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProductID int64  `json:"product_id"`
+		VariantID *int64 `json:"variant_id"`
+		Message   string `json:"message"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", req.ProductID).Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists == 0 {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	}
+	res, err := db.Exec(
+		"INSERT INTO restock_requests (user_id, product_id, variant_id, message, created_at) VALUES (?, ?, ?, ?, ?)",
+		currentUserID(r), req.ProductID, req.VariantID, req.Message, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "permintaan restock dicatat"})
+}
+
+// GetRestockRequests lists every restock request for admins and stockers.
+func GetRestockRequests(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		"SELECT id, user_id, product_id, variant_id, message, created_at FROM restock_requests ORDER BY created_at DESC")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	requests := []RestockRequest{}
+	for rows.Next() {
+		var (
+			rr        RestockRequest
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&rr.ID, &rr.UserID, &rr.ProductID, &variantID, &rr.Message, &rr.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if variantID.Valid {
+			rr.VariantID = &variantID.Int64
+		}
+		requests = append(requests, rr)
+	}
+	respondJSON(w, http.StatusOK, requests)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+func registerReturnRoutes(r *Router) {
+	r.POST("/api/v1/orders/:id/returns", AuthMiddleware(CreateReturnRequest))
+	r.GET("/api/v1/orders/:id/returns", AuthMiddleware(GetOrderReturns))
+	r.GET("/api/v1/admin/returns", RoleMiddleware("admin")(GetAllReturns))
+	r.POST("/api/v1/admin/returns/:id/approve", RoleMiddleware("admin")(ApproveReturn))
+	r.POST("/api/v1/admin/returns/:id/reject", RoleMiddleware("admin")(RejectReturn))
+}
+
+// ReturnRequest is an RMA filed by a user against a delivered order.
+type ReturnRequest struct {
+	ID         int64      `json:"id"`
+	OrderID    int64      `json:"order_id"`
+	UserID     int64      `json:"user_id"`
+	Reason     string     `json:"reason"`
+	Photos     []string   `json:"photos"`
+	Status     string     `json:"status"`
+	Resolution *string    `json:"resolution"`
+	Restocked  bool       `json:"restocked"`
+	AdminNote  *string    `json:"admin_note"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+}
+
+const returnColumns = "id, order_id, user_id, reason, photos, status, resolution, restocked, admin_note, created_at, resolved_at"
+
+func scanReturn(row interface{ Scan(...any) error }) (ReturnRequest, error) {
+	var (
+		rr         ReturnRequest
+		photos     string
+		resolution sql.NullString
+		adminNote  sql.NullString
+		resolvedAt sql.NullTime
+	)
+	err := row.Scan(&rr.ID, &rr.OrderID, &rr.UserID, &rr.Reason, &photos, &rr.Status,
+		&resolution, &rr.Restocked, &adminNote, &rr.CreatedAt, &resolvedAt)
+	if err != nil {
+		return rr, err
+	}
+	if photos != "" {
+		json.Unmarshal([]byte(photos), &rr.Photos)
+	}
+	if rr.Photos == nil {
+		rr.Photos = []string{}
+	}
+	if resolution.Valid {
+		rr.Resolution = &resolution.String
+	}
+	if adminNote.Valid {
+		rr.AdminNote = &adminNote.String
+	}
+	if resolvedAt.Valid {
+		rr.ResolvedAt = &resolvedAt.Time
+	}
+	return rr, nil
+}
+
+// CreateReturnRequest files a return on one of the user's delivered orders.
+func CreateReturnRequest(w http.ResponseWriter, r *http.Request) {
+	orderID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Reason string   `json:"reason"`
+		Photos []string `json:"photos"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Reason == "" {
+		respondError(w, http.StatusBadRequest, "alasan retur wajib diisi")
+		return
+	}
+	userID := currentUserID(r)
+	var status string
+	err = db.QueryRow("SELECT status FROM orders WHERE id = ? AND user_id = ?", orderID, userID).Scan(&status)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if status != "delivered" {
+		respondError(w, http.StatusBadRequest, "retur hanya bisa diajukan untuk order yang sudah delivered")
+		return
+	}
+	var open int
+	if err := db.QueryRow(
+		"SELECT COUNT(*) FROM returns WHERE order_id = ? AND status = 'requested'", orderID).Scan(&open); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if open > 0 {
+		respondError(w, http.StatusConflict, "masih ada pengajuan retur yang belum diproses untuk order ini")
+		return
+	}
+	photos, _ := json.Marshal(req.Photos)
+	res, err := db.Exec(
+		"INSERT INTO returns (order_id, user_id, reason, photos, status, created_at) VALUES (?, ?, ?, ?, 'requested', ?)",
+		orderID, userID, req.Reason, string(photos), nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "pengajuan retur dicatat"})
+}
+
+// GetOrderReturns lists the return requests of one of the user's orders.
+func GetOrderReturns(w http.ResponseWriter, r *http.Request) {
+	orderID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	rows, err := db.Query(
+		"SELECT "+returnColumns+" FROM returns WHERE order_id = ? AND user_id = ? ORDER BY created_at DESC",
+		orderID, currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	returns := []ReturnRequest{}
+	for rows.Next() {
+		rr, err := scanReturn(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		returns = append(returns, rr)
+	}
+	respondJSON(w, http.StatusOK, returns)
+}
+
+// GetAllReturns lists every return request for admins.
+func GetAllReturns(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT " + returnColumns + " FROM returns ORDER BY created_at DESC")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	returns := []ReturnRequest{}
+	for rows.Next() {
+		rr, err := scanReturn(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		returns = append(returns, rr)
+	}
+	respondJSON(w, http.StatusOK, returns)
+}
+
+// restockReturnedOrder puts the stock of every order line back into inventory
+// inside the caller's transaction.
+func restockReturnedOrder(q dbtx, orderID int64) error {
+	items, err := fetchOrderItems(orderID)
+	if err != nil {
+		return err
+	}
+	for _, it := range items {
+		if it.BundleID != nil {
+			components, err := fetchBundleItems(*it.BundleID)
+			if err != nil {
+				return err
+			}
+			for _, c := range components {
+				variantID := sql.NullInt64{}
+				if c.VariantID != nil {
+					variantID = sql.NullInt64{Int64: *c.VariantID, Valid: true}
+				}
+				if err := restoreStock(q, c.ProductID, variantID, c.Quantity*it.Quantity); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		variantID := sql.NullInt64{}
+		if it.VariantID != nil {
+			variantID = sql.NullInt64{Int64: *it.VariantID, Valid: true}
+		}
+		if err := restoreStock(q, *it.ProductID, variantID, it.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApproveReturn resolves a return as refund or replacement, optionally restocking.
+func ApproveReturn(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Resolution string `json:"resolution"`
+		Restock    bool   `json:"restock"`
+		Note       string `json:"note"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Resolution != "refund" && req.Resolution != "replacement" {
+		respondError(w, http.StatusBadRequest, "resolution harus refund atau replacement")
+		return
+	}
+	var (
+		orderID int64
+		userID  int64
+		status  string
+	)
+	err = db.QueryRow("SELECT order_id, user_id, status FROM returns WHERE id = ?", id).
+		Scan(&orderID, &userID, &status)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "pengajuan retur tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if status != "requested" {
+		respondError(w, http.StatusBadRequest, "pengajuan retur sudah diproses")
+		return
+	}
+	// The conditional UPDATE is the real gate: if a concurrent approval got
+	// there first it matches zero rows and this one rolls back without
+	// restocking a second time.
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"UPDATE returns SET status = 'approved', resolution = ?, restocked = ?, admin_note = ?, resolved_at = ? WHERE id = ? AND status = 'requested'",
+			req.Resolution, req.Restock, req.Note, nowUTC(), id)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return sql.ErrNoRows
+		}
+		if req.Restock {
+			return restockReturnedOrder(tx, orderID)
+		}
+		return nil
+	})
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusConflict, "pengajuan retur sudah diproses")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if req.Resolution == "refund" {
+		createNotification(userID, "Retur disetujui",
+			"Pengajuan retur kamu disetujui. Refund akan segera diproses.")
+	} else {
+		createNotification(userID, "Retur disetujui",
+			"Pengajuan retur kamu disetujui. Barang pengganti akan segera dikirim.")
+	}
+	respondMessage(w, http.StatusOK, "retur berhasil disetujui")
+}
+
+// RejectReturn declines a return request.
+func RejectReturn(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	decodeBody(r, &req)
+	var (
+		userID int64
+		status string
+	)
+	err = db.QueryRow("SELECT user_id, status FROM returns WHERE id = ?", id).Scan(&userID, &status)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "pengajuan retur tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if status != "requested" {
+		respondError(w, http.StatusBadRequest, "pengajuan retur sudah diproses")
+		return
+	}
+	if _, err := db.Exec(
+		"UPDATE returns SET status = 'rejected', admin_note = ?, resolved_at = ? WHERE id = ?",
+		req.Note, nowUTC(), id); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	createNotification(userID, "Retur ditolak", "Pengajuan retur kamu ditolak. "+req.Note)
+	respondMessage(w, http.StatusOK, "retur berhasil ditolak")
+}
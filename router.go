@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Router is a small HTTP router supporting method matching and :param path segments.
+type Router struct {
+	routes []route
+}
+
+type route struct {
+	method   string
+	segments []string
+	handler  http.HandlerFunc
+}
+
+type paramsKey struct{}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router { return &Router{} }
+
+// Handle registers a handler for the given method and path pattern.
+func (rt *Router) Handle(method, pattern string, handler http.HandlerFunc) {
+	rt.routes = append(rt.routes, route{method, splitPath(pattern), handler})
+}
+
+// GET registers a handler for GET requests.
+func (rt *Router) GET(pattern string, h http.HandlerFunc) { rt.Handle(http.MethodGet, pattern, h) }
+
+// POST registers a handler for POST requests.
+func (rt *Router) POST(pattern string, h http.HandlerFunc) { rt.Handle(http.MethodPost, pattern, h) }
+
+// PUT registers a handler for PUT requests.
+func (rt *Router) PUT(pattern string, h http.HandlerFunc) { rt.Handle(http.MethodPut, pattern, h) }
+
+// PATCH registers a handler for PATCH requests.
+func (rt *Router) PATCH(pattern string, h http.HandlerFunc) { rt.Handle(http.MethodPatch, pattern, h) }
+
+// DELETE registers a handler for DELETE requests.
+func (rt *Router) DELETE(pattern string, h http.HandlerFunc) {
+	rt.Handle(http.MethodDelete, pattern, h)
+}
+
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := splitPath(r.URL.Path)
+	methodMismatch := false
+	for _, ro := range rt.routes {
+		params, ok := matchRoute(ro.segments, segments)
+		if !ok {
+			continue
+		}
+		if ro.method != r.Method {
+			methodMismatch = true
+			continue
+		}
+		ctx := context.WithValue(r.Context(), paramsKey{}, params)
+		ro.handler(w, r.WithContext(ctx))
+		return
+	}
+	if methodMismatch {
+		respondError(w, http.StatusMethodNotAllowed, "method tidak diizinkan")
+		return
+	}
+	respondError(w, http.StatusNotFound, "route tidak ditemukan")
+}
+
+func matchRoute(pattern, path []string) (map[string]string, bool) {
+	if len(pattern) != len(path) {
+		return nil, false
+	}
+	var params map[string]string
+	for i, seg := range pattern {
+		if strings.HasPrefix(seg, ":") {
+			if params == nil {
+				params = make(map[string]string)
+			}
+			params[seg[1:]] = path[i]
+			continue
+		}
+		if seg != path[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// Param returns the named path parameter captured by the router.
+func Param(r *http.Request, name string) string {
+	if params, ok := r.Context().Value(paramsKey{}).(map[string]string); ok {
+		return params[name]
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"time"
+)
+
+// StartReservationExpiryWorker periodically expires overdue waitToBuy orders
+// and standalone reservations, restoring stock and costing the user a heart.
+func StartReservationExpiryWorker() {
+	go func() {
+		for range time.Tick(time.Minute) {
+			expireOverdueOrders()
+			expireOverdueReservations()
+		}
+	}()
+}
+
+func expireOverdueOrders() {
+	rows, err := db.Query(
+		"SELECT id, user_id FROM orders WHERE status = 'waitToBuy' AND timer_expiration < ?", nowUTC())
+	if err != nil {
+		log.Printf("gagal cek order expired: %v", err)
+		return
+	}
+	type expired struct{ orderID, userID int64 }
+	var overdue []expired
+	for rows.Next() {
+		var e expired
+		if err := rows.Scan(&e.orderID, &e.userID); err != nil {
+			rows.Close()
+			log.Printf("gagal scan order expired: %v", err)
+			return
+		}
+		overdue = append(overdue, e)
+	}
+	rows.Close()
+	for _, e := range overdue {
+		err := WithTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(
+				"UPDATE orders SET status = 'expired', expired_at = ? WHERE id = ?", nowUTC(), e.orderID); err != nil {
+				return err
+			}
+			if err := releaseReservationsForOrder(tx, e.orderID); err != nil {
+				return err
+			}
+			_, err := adjustHearts(tx, e.userID, -1, "order_expired", e.orderID)
+			return err
+		})
+		if err != nil {
+			log.Printf("gagal expire order %d: %v", e.orderID, err)
+			continue
+		}
+		recordActivity(e.userID, "order_expired", "Order kadaluarsa karena tidak dibayar", e.orderID)
+		createNotification(e.userID, "Order kadaluarsa",
+			"Order kamu kadaluarsa karena tidak dibayar sebelum timer habis. Hearts berkurang 1.")
+	}
+}
+
+// releaseReservationsForOrder returns reserved stock of an order back to
+// inventory, inside the caller's transaction.
+func releaseReservationsForOrder(q dbtx, orderID int64) error {
+	rows, err := q.Query(
+		"SELECT product_id, variant_id, quantity FROM stock_reservations WHERE order_id = ? AND status = 'active'",
+		orderID)
+	if err != nil {
+		return err
+	}
+	type held struct {
+		productID int64
+		variantID sql.NullInt64
+		quantity  int
+	}
+	var holds []held
+	for rows.Next() {
+		var h held
+		if err := rows.Scan(&h.productID, &h.variantID, &h.quantity); err != nil {
+			rows.Close()
+			return err
+		}
+		holds = append(holds, h)
+	}
+	rows.Close()
+	for _, h := range holds {
+		if err := restoreStock(q, h.productID, h.variantID, h.quantity); err != nil {
+			return err
+		}
+	}
+	_, err = q.Exec(
+		"UPDATE stock_reservations SET status = 'expired', status_changed_at = ? WHERE order_id = ? AND status = 'active'",
+		nowUTC(), orderID)
+	return err
+}
+
+func expireOverdueReservations() {
+	rows, err := db.Query(
+		`SELECT id, user_id, product_id, variant_id, quantity FROM stock_reservations
+		 WHERE status = 'active' AND order_id IS NULL AND expires_at < ?`, nowUTC())
+	if err != nil {
+		log.Printf("gagal cek reservasi expired: %v", err)
+		return
+	}
+	type held struct {
+		id, userID, productID int64
+		variantID             sql.NullInt64
+		quantity              int
+	}
+	var holds []held
+	for rows.Next() {
+		var h held
+		if err := rows.Scan(&h.id, &h.userID, &h.productID, &h.variantID, &h.quantity); err != nil {
+			rows.Close()
+			log.Printf("gagal scan reservasi expired: %v", err)
+			return
+		}
+		holds = append(holds, h)
+	}
+	rows.Close()
+	for _, h := range holds {
+		err := WithTx(func(tx *sql.Tx) error {
+			if err := restoreStock(tx, h.productID, h.variantID, h.quantity); err != nil {
+				return err
+			}
+			if _, err := tx.Exec("UPDATE stock_reservations SET status = 'expired', status_changed_at = ? WHERE id = ?", nowUTC(), h.id); err != nil {
+				return err
+			}
+			_, err := adjustHearts(tx, h.userID, -1, "reservation_expired", h.id)
+			return err
+		})
+		if err != nil {
+			log.Printf("gagal expire reservasi %d: %v", h.id, err)
+			continue
+		}
+		createNotification(h.userID, "Reservasi kadaluarsa",
+			"Reservasi stock kamu kadaluarsa. Hearts berkurang 1.")
+	}
+}
+
+func restoreStock(q dbtx, productID int64, variantID sql.NullInt64, quantity int) error {
+	var vID *int64
+	if variantID.Valid {
+		vID = &variantID.Int64
+	}
+	return stockSvc.Release(q, productID, vID, quantity)
+}
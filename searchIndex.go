@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// SearchIndexer pushes catalog changes into an external search engine and
+// answers storefront queries with typo-tolerant results.
+type SearchIndexer interface {
+	IndexProduct(p Product) error
+	DeleteProduct(id int64) error
+	Search(q string, limit int) ([]map[string]any, error)
+}
+
+// meiliIndexer talks to a Meilisearch instance over its REST API.
+type meiliIndexer struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// searchIndexer is nil when no engine is configured; callers must check.
+var searchIndexer SearchIndexer
+
+// initSearchIndexer wires up the optional engine from SEARCH_ENGINE_URL.
+func initSearchIndexer() {
+	if config.SearchEngineURL == "" {
+		return
+	}
+	searchIndexer = &meiliIndexer{
+		baseURL: config.SearchEngineURL,
+		apiKey:  config.SearchAPIKey,
+		client:  &http.Client{Timeout: 5 * time.Second},
+	}
+	log.Printf("search engine aktif di %s", config.SearchEngineURL)
+}
+
+func (m *meiliIndexer) do(method, path string, body any) (*http.Response, error) {
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			return nil, err
+		}
+	}
+	req, err := http.NewRequest(method, m.baseURL+path, &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("search engine balas status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func (m *meiliIndexer) IndexProduct(p Product) error {
+	doc := map[string]any{
+		"id":             p.ID,
+		"category_id":    p.CategoryID,
+		"name":           p.Name,
+		"description":    p.Description,
+		"price":          p.Price,
+		"is_discounted":  p.IsDiscounted,
+		"discount_price": p.DiscountPrice,
+		"is_service":     p.IsService,
+	}
+	resp, err := m.do(http.MethodPost, "/indexes/products/documents", []map[string]any{doc})
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (m *meiliIndexer) DeleteProduct(id int64) error {
+	resp, err := m.do(http.MethodDelete, fmt.Sprintf("/indexes/products/documents/%d", id), nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (m *meiliIndexer) Search(q string, limit int) ([]map[string]any, error) {
+	resp, err := m.do(http.MethodPost, "/indexes/products/search",
+		map[string]any{"q": q, "limit": limit, "facets": []string{"category_id"}})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Hits []map[string]any `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	return body.Hits, nil
+}
+
+// indexProductByID reloads a product row and pushes it to the engine.
+func indexProductByID(id int64) {
+	if searchIndexer == nil {
+		return
+	}
+	p, err := scanProduct(db.QueryRow("SELECT "+productColumns+" FROM products WHERE id = ?", id))
+	if err != nil {
+		log.Printf("gagal ambil produk %d untuk indexing: %v", id, err)
+		return
+	}
+	// Non-published products stay out of the public search index entirely.
+	if p.Status != "published" {
+		removeProductFromIndex(id)
+		return
+	}
+	if err := searchIndexer.IndexProduct(p); err != nil {
+		log.Printf("gagal index produk %d: %v", id, err)
+	}
+}
+
+// removeProductFromIndex drops a product document from the engine.
+func removeProductFromIndex(id int64) {
+	if searchIndexer == nil {
+		return
+	}
+	if err := searchIndexer.DeleteProduct(id); err != nil {
+		log.Printf("gagal hapus produk %d dari index: %v", id, err)
+	}
+}
+
+func registerSearchIndexRoutes(r *Router) {
+	r.GET("/api/v1/search", SearchProducts)
+	r.POST("/api/v1/admin/search/reindex", RoleMiddleware("admin")(ReindexProducts))
+}
+
+// SearchProducts queries the engine when configured, otherwise falls back to a
+// plain LIKE query against the database.
+func SearchProducts(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if q == "" {
+		respondError(w, http.StatusBadRequest, "parameter q wajib diisi")
+		return
+	}
+	if searchIndexer != nil {
+		hits, err := searchIndexer.Search(q, 20)
+		if err != nil {
+			respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, hits)
+		return
+	}
+	rows, err := db.Query(
+		"SELECT "+productColumns+" FROM products WHERE name LIKE ? OR description LIKE ? LIMIT 20",
+		"%"+q+"%", "%"+q+"%")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	products := []Product{}
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		products = append(products, p)
+	}
+	respondJSON(w, http.StatusOK, products)
+}
+
+// ReindexProducts pushes the whole catalog into the engine again.
+func ReindexProducts(w http.ResponseWriter, r *http.Request) {
+	if searchIndexer == nil {
+		respondError(w, http.StatusServiceUnavailable, "search engine belum dikonfigurasi")
+		return
+	}
+	rows, err := db.Query("SELECT " + productColumns + " FROM products WHERE status = 'published' AND deleted_at IS NULL")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	indexed := 0
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := searchIndexer.IndexProduct(p); err != nil {
+			respondError(w, http.StatusBadGateway, err.Error())
+			return
+		}
+		indexed++
+	}
+	respondJSON(w, http.StatusOK, map[string]any{"indexed": indexed, "message": "reindex selesai"})
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+func registerSearchRoutes(r *Router) {
+	r.GET("/api/v1/search/suggest", SearchSuggest)
+}
+
+// SearchSuggestion is the lightweight payload returned per keystroke.
+type SearchSuggestion struct {
+	Products   []string `json:"products"`
+	Categories []string `json:"categories"`
+}
+
+// suggestCache memoizes suggestion responses per prefix for a short window so
+// repeated keystrokes do not hammer the database.
+type suggestCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[string]suggestCacheEntry
+}
+
+type suggestCacheEntry struct {
+	value     SearchSuggestion
+	expiresAt time.Time
+}
+
+func newSuggestCache(ttl time.Duration, maxSize int) *suggestCache {
+	return &suggestCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[string]suggestCacheEntry),
+	}
+}
+
+func (c *suggestCache) get(key string) (SearchSuggestion, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || nowUTC().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return SearchSuggestion{}, false
+	}
+	return entry.value, true
+}
+
+func (c *suggestCache) set(key string, value SearchSuggestion) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= c.maxSize {
+		// Drop expired entries first; if still full, reset the whole map.
+		now := nowUTC()
+		for k, e := range c.entries {
+			if now.After(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxSize {
+			c.entries = make(map[string]suggestCacheEntry)
+		}
+	}
+	c.entries[key] = suggestCacheEntry{value: value, expiresAt: nowUTC().Add(c.ttl)}
+}
+
+var suggestions = newSuggestCache(30*time.Second, 500)
+
+// SearchSuggest returns product and category name suggestions by prefix.
+func SearchSuggest(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(strings.ToLower(r.URL.Query().Get("q")))
+	if q == "" {
+		respondError(w, http.StatusBadRequest, "parameter q wajib diisi")
+		return
+	}
+	if cached, ok := suggestions.get(q); ok {
+		respondJSON(w, http.StatusOK, cached)
+		return
+	}
+	result := SearchSuggestion{Products: []string{}, Categories: []string{}}
+	prefix := q + "%"
+	rows, err := readOnlyDB().Query("SELECT name FROM products WHERE name LIKE ? ORDER BY name LIMIT 8", prefix)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		result.Products = append(result.Products, name)
+	}
+	rows.Close()
+	rows, err = readOnlyDB().Query("SELECT name FROM categories WHERE name LIKE ? ORDER BY name LIMIT 4", prefix)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		result.Categories = append(result.Categories, name)
+	}
+	rows.Close()
+	suggestions.set(q, result)
+	respondJSON(w, http.StatusOK, result)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSuggestCacheHitAndExpiry(t *testing.T) {
+	c := newSuggestCache(50*time.Millisecond, 10)
+	value := SearchSuggestion{Products: []string{"kopi"}, Categories: []string{"minuman"}}
+	c.set("ko", value)
+
+	got, ok := c.get("ko")
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if len(got.Products) != 1 || got.Products[0] != "kopi" {
+		t.Fatalf("unexpected cached value: %+v", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, ok := c.get("ko"); ok {
+		t.Fatal("expected entry to expire")
+	}
+}
+
+func TestSuggestCacheEvictsWhenFull(t *testing.T) {
+	c := newSuggestCache(time.Minute, 2)
+	c.set("a", SearchSuggestion{})
+	c.set("b", SearchSuggestion{})
+	c.set("c", SearchSuggestion{})
+	if _, ok := c.get("c"); !ok {
+		t.Fatal("expected latest entry to be cached")
+	}
+}
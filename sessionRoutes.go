@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+func registerSessionRoutes(r *Router) {
+	r.GET("/api/v1/me/sessions", AuthMiddleware(GetMySessions))
+	r.DELETE("/api/v1/me/sessions", AuthMiddleware(RevokeMySessions))
+}
+
+// Session is an active refresh token presented with the metadata captured at
+// login.
+type Session struct {
+	ID        int64  `json:"id"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	CreatedAt string `json:"created_at"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// GetMySessions lists the account's active sessions, newest first.
+func GetMySessions(w http.ResponseWriter, r *http.Request) {
+	claims := currentClaims(r)
+	rows, err := db.Query(
+		`SELECT id, ip, user_agent, created_at, expires_at FROM refresh_tokens
+		 WHERE account_id = ? AND role = ? AND revoked_at IS NULL AND expires_at > ?
+		 ORDER BY id DESC`,
+		claims.ID, claims.Role, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	sessions := []Session{}
+	for rows.Next() {
+		var (
+			s                    Session
+			createdAt, expiresAt time.Time
+		)
+		if err := rows.Scan(&s.ID, &s.IP, &s.UserAgent, &createdAt, &expiresAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.CreatedAt = displayTime(createdAt)
+		s.ExpiresAt = displayTime(expiresAt)
+		sessions = append(sessions, s)
+	}
+	respondJSON(w, http.StatusOK, sessions)
+}
+
+// RevokeMySessions kills every active session of the account, typically right
+// after a credential change. The current access token keeps working until it
+// expires; only refreshing is cut off.
+func RevokeMySessions(w http.ResponseWriter, r *http.Request) {
+	claims := currentClaims(r)
+	if err := revokeRefreshTokens(claims.ID, claims.Role); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "semua sesi sudah dicabut")
+}
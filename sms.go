@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// SMSSender sends one text message. Like Mailer, the implementation is chosen
+// by config so dev runs never text real numbers.
+type SMSSender interface {
+	SendSMS(to, message string) error
+}
+
+func newSMSSender() SMSSender {
+	switch config.SMSProvider {
+	case "twilio":
+		return twilioSMSSender{}
+	default:
+		return logSMSSender{}
+	}
+}
+
+// logSMSSender is the dev implementation.
+type logSMSSender struct{}
+
+func (logSMSSender) SendSMS(to, message string) error {
+	log.Printf("sms (log-only) ke %s: %s", to, message)
+	return nil
+}
+
+// twilioSMSSender sends through the Twilio messages API.
+type twilioSMSSender struct{}
+
+func (twilioSMSSender) SendSMS(to, message string) error {
+	endpoint := "https://api.twilio.com/2010-04-01/Accounts/" + config.TwilioSID + "/Messages.json"
+	form := url.Values{
+		"To":   {to},
+		"From": {config.SMSFrom},
+		"Body": {message},
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(config.TwilioSID, config.TwilioToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("twilio menjawab status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+var smsSender SMSSender = logSMSSender{}
+
+// initSMSSender picks the configured implementation; called from main after
+// LoadConfig.
+func initSMSSender() {
+	smsSender = newSMSSender()
+}
+
+// sendSMSAsync fires a text in the background, logging failures, so handlers
+// never block on the provider.
+func sendSMSAsync(to, message string) {
+	go func() {
+		if err := smsSender.SendSMS(to, message); err != nil {
+			log.Printf("gagal kirim sms ke %s: %v", to, err)
+		}
+	}()
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Soft-deletable entities carry a deleted_at column instead of losing rows on
+// DELETE. Their read queries append notDeleted(...) so deleted rows vanish
+// from the API, while admins can still see them with ?include_deleted=true
+// and bring them back through the restore endpoint.
+
+func registerSoftDeleteRoutes(r *Router) {
+	r.POST("/api/v1/admin/restore/:entity/:id", RoleMiddleware("admin")(RestoreDeleted))
+}
+
+// softDeletables maps the restore endpoint's entity segment to its table.
+var softDeletables = map[string]string{
+	"categories":    "categories",
+	"products":      "products",
+	"variants":      "product_variants",
+	"images":        "product_images",
+	"notifications": "notifications",
+	"coupons":       "coupons",
+}
+
+// softDelete stamps deleted_at on a row; it reports false when the row does
+// not exist or is already deleted.
+func softDelete(table string, id int64) (bool, error) {
+	res, err := db.Exec(
+		"UPDATE "+table+" SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", nowUTC(), id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// notDeleted returns the filter clause for live rows; prefix qualifies the
+// column in joined queries (e.g. notDeleted("v.")).
+func notDeleted(prefix string) string {
+	return " AND " + prefix + "deleted_at IS NULL"
+}
+
+// includeDeleted reports whether the caller is an admin asking to see
+// deleted rows too.
+func includeDeleted(r *http.Request) bool {
+	claims := currentClaims(r)
+	return claims != nil && claims.Role == "admin" && r.URL.Query().Get("include_deleted") == "true"
+}
+
+// RestoreDeleted clears deleted_at on one row of a soft-deletable entity.
+func RestoreDeleted(w http.ResponseWriter, r *http.Request) {
+	table, ok := softDeletables[Param(r, "entity")]
+	if !ok {
+		respondError(w, http.StatusNotFound, "entity tidak bisa direstore")
+		return
+	}
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("UPDATE "+table+" SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "tidak ada row terhapus dengan id itu")
+		return
+	}
+	respondMessage(w, http.StatusOK, "row berhasil direstore")
+}
+
+// productPurgeAfter is how long a soft-deleted product lingers before the
+// purge worker removes it for good.
+const productPurgeAfter = 30 * 24 * time.Hour
+
+// StartProductPurgeWorker hard-deletes products that have been soft-deleted
+// for longer than productPurgeAfter and are no longer referenced by any
+// order or cart, so the catalog table doesn't accumulate dead rows forever.
+func StartProductPurgeWorker() {
+	go func() {
+		for range time.Tick(24 * time.Hour) {
+			purgeDeletedProducts()
+		}
+	}()
+}
+
+func purgeDeletedProducts() {
+	rows, err := db.Query(
+		`SELECT p.id FROM products p
+		 WHERE p.deleted_at IS NOT NULL AND p.deleted_at < ?
+		   AND NOT EXISTS (SELECT 1 FROM order_items oi WHERE oi.product_id = p.id)
+		   AND NOT EXISTS (SELECT 1 FROM cart_items ci WHERE ci.product_id = p.id)`,
+		nowUTC().Add(-productPurgeAfter))
+	if err != nil {
+		log.Printf("gagal cek produk untuk purge: %v", err)
+		return
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			log.Printf("gagal scan produk untuk purge: %v", err)
+			return
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	for _, id := range ids {
+		err := WithTx(func(tx *sql.Tx) error {
+			for _, stmt := range []string{
+				"DELETE FROM product_variants WHERE product_id = ?",
+				"DELETE FROM product_images WHERE product_id = ?",
+				"DELETE FROM wishlists WHERE product_id = ?",
+				"DELETE FROM products WHERE id = ?",
+			} {
+				if _, err := tx.Exec(stmt, id); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("gagal purge produk %d: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerStockLedgerRoutes(r *Router) {
+	r.GET("/api/v1/admin/stock-movements", RoleMiddleware("admin", "stocker")(GetStockMovements))
+}
+
+// StockMovement is one ledger entry of stock changing hands.
+type StockMovement struct {
+	ID        int64     `json:"id"`
+	ProductID int64     `json:"product_id"`
+	VariantID *int64    `json:"variant_id"`
+	Delta     int       `json:"delta"`
+	Reason    string    `json:"reason"`
+	RefID     int64     `json:"ref_id"`
+	ActorID   int64     `json:"actor_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// recordStockMovement writes one row to the stock_movements ledger. Failures
+// are logged rather than surfaced so a ledger hiccup never blocks the actual
+// stock change.
+func recordStockMovement(productID int64, variantID *int64, delta int, reason string, refID int64, actorID int64) {
+	if _, err := db.Exec(
+		`INSERT INTO stock_movements (product_id, variant_id, delta, reason, ref_id, actor_id, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		productID, variantID, delta, reason, refID, actorID, nowUTC()); err != nil {
+		log.Printf("gagal mencatat stock movement produk %d: %v", productID, err)
+	}
+}
+
+// applyStockDelta shifts stock on the product or variant row and records the
+// movement in the ledger.
+func applyStockDelta(productID int64, variantID *int64, delta int, reason string, refID int64, actorID int64) error {
+	if err := stockSvc.Adjust(db, productID, variantID, delta); err != nil {
+		return err
+	}
+	recordStockMovement(productID, variantID, delta, reason, refID, actorID)
+	recordAuditAs(actorID, "", "adjust", "stock", productID, nil,
+		map[string]any{"variant_id": variantID, "delta": delta, "reason": reason})
+	return nil
+}
+
+// applyWarehouseStockDelta is applyStockDelta for a change tied to one
+// warehouse: the delta lands on that warehouse's row as well as the
+// aggregate, and the warehouse is kept as the ledger ref.
+func applyWarehouseStockDelta(warehouseID int64, productID int64, variantID *int64, delta int, actorID int64) error {
+	if err := stockSvc.AdjustAt(db, warehouseID, productID, variantID, delta); err != nil {
+		return err
+	}
+	recordStockMovement(productID, variantID, delta, "warehouse_adjust", warehouseID, actorID)
+	recordAuditAs(actorID, "", "adjust", "stock", productID, nil,
+		map[string]any{"variant_id": variantID, "delta": delta, "reason": "warehouse_adjust", "warehouse_id": warehouseID})
+	return nil
+}
+
+// GetStockMovements lists the stock ledger newest-first with keyset
+// pagination; it always pages because this table grows without bound.
+func GetStockMovements(w http.ResponseWriter, r *http.Request) {
+	k := keysetParams(r)
+	if !k.Enabled {
+		k = keyset{Enabled: true, Cursor: queryInt(r, "cursor", 0), Limit: 50}
+	}
+	query := "SELECT id, product_id, variant_id, delta, reason, ref_id, actor_id, created_at FROM stock_movements"
+	args := []any{}
+	if k.Cursor > 0 {
+		query += " WHERE id < ?"
+		args = append(args, k.Cursor)
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, k.Limit)
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	movements := []StockMovement{}
+	for rows.Next() {
+		var (
+			m         StockMovement
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&m.ID, &m.ProductID, &variantID, &m.Delta, &m.Reason,
+			&m.RefID, &m.ActorID, &m.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if variantID.Valid {
+			m.VariantID = &variantID.Int64
+		}
+		movements = append(movements, m)
+	}
+	var lastID int64
+	if len(movements) > 0 {
+		lastID = movements[len(movements)-1].ID
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"items":       movements,
+		"next_cursor": nextCursor(lastID, len(movements), k),
+	})
+}
@@ -0,0 +1,154 @@
+package main
+
+import "errors"
+
+// errWarehouseStockMissing is returned when a decrement targets a warehouse
+// row that does not exist; handlers map it to 404.
+var errWarehouseStockMissing = errors.New("stock warehouse tidak ditemukan")
+
+// StockService centralizes the product-versus-variant stock branching so
+// every path that touches inventory (cart, checkout, reservations, expiry,
+// POS, receiving) moves stock the same way. The aggregate stock columns on
+// products and product_variants stay authoritative for availability; every
+// adjustment is also mirrored onto warehouse_stock rows so the per-warehouse
+// breakdown follows sales and restocks instead of drifting.
+type StockService struct{}
+
+// stockSvc is the shared instance; the service is stateless.
+var stockSvc StockService
+
+// Get returns the current stock of a product or, when variantID is set, of
+// that variant.
+func (StockService) Get(productID int64, variantID *int64) (int, error) {
+	var stock int
+	if variantID != nil {
+		err := db.QueryRow("SELECT stock FROM product_variants WHERE id = ?", *variantID).Scan(&stock)
+		return stock, err
+	}
+	err := db.QueryRow("SELECT stock FROM products WHERE id = ?", productID).Scan(&stock)
+	return stock, err
+}
+
+// Reserve takes quantity out of inventory inside the caller's transaction.
+func (StockService) Reserve(q dbtx, productID int64, variantID *int64, quantity int) error {
+	return stockSvc.Adjust(q, productID, variantID, -quantity)
+}
+
+// Release puts quantity back into inventory inside the caller's transaction.
+func (StockService) Release(q dbtx, productID int64, variantID *int64, quantity int) error {
+	return stockSvc.Adjust(q, productID, variantID, quantity)
+}
+
+// Adjust shifts stock by delta on the product or variant row. Decrements also
+// run the low-stock check so every outgoing path alerts staff the same way.
+// The delta is mirrored onto warehouse rows without a preferred warehouse;
+// use AdjustAt when the caller knows which warehouse the stock moved in.
+func (StockService) Adjust(q dbtx, productID int64, variantID *int64, delta int) error {
+	return stockSvc.AdjustAt(q, 0, productID, variantID, delta)
+}
+
+// AdjustAt is Adjust pinned to one warehouse: the aggregate column moves by
+// delta and the same delta lands on that warehouse's row. warehouseID 0 means
+// no particular warehouse, in which case decrements drain the fullest rows
+// first and increments top up the fullest row.
+func (StockService) AdjustAt(q dbtx, warehouseID int64, productID int64, variantID *int64, delta int) error {
+	var err error
+	if variantID != nil {
+		_, err = q.Exec("UPDATE product_variants SET stock = stock + ? WHERE id = ?", delta, *variantID)
+	} else {
+		_, err = q.Exec("UPDATE products SET stock = stock + ? WHERE id = ?", delta, productID)
+	}
+	if err != nil {
+		return err
+	}
+	if err := applyWarehouseDelta(q, warehouseID, productID, variantID, delta); err != nil {
+		return err
+	}
+	if delta < 0 {
+		checkLowStock(q, productID, variantID, delta)
+	}
+	return nil
+}
+
+// applyWarehouseDelta mirrors an aggregate stock change onto warehouse_stock.
+// Deployments that never created warehouse rows are left alone: with nothing
+// to mirror onto, the aggregate column simply keeps working as before.
+func applyWarehouseDelta(q dbtx, warehouseID int64, productID int64, variantID *int64, delta int) error {
+	if warehouseID != 0 {
+		// <=> matches NULL variant_id rows too, so product-level and
+		// variant-level entries share one code path.
+		res, err := q.Exec(
+			"UPDATE warehouse_stock SET stock = stock + ? WHERE warehouse_id = ? AND product_id = ? AND variant_id <=> ?",
+			delta, warehouseID, productID, variantID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			if delta < 0 {
+				return errWarehouseStockMissing
+			}
+			_, err = q.Exec(
+				"INSERT INTO warehouse_stock (warehouse_id, product_id, variant_id, stock) VALUES (?, ?, ?, ?)",
+				warehouseID, productID, variantID, delta)
+		}
+		return err
+	}
+	if delta > 0 {
+		// Incoming stock with no stated warehouse lands on the fullest row.
+		// The derived table works around MySQL refusing to reference the
+		// updated table in a subquery.
+		_, err := q.Exec(
+			`UPDATE warehouse_stock SET stock = stock + ? WHERE id = (
+			   SELECT id FROM (
+			     SELECT id FROM warehouse_stock WHERE product_id = ? AND variant_id <=> ?
+			     ORDER BY stock DESC, warehouse_id LIMIT 1
+			   ) t)`,
+			delta, productID, variantID)
+		return err
+	}
+	// Outgoing stock drains the fullest rows first. Rows are collected before
+	// updating because the MySQL driver cannot run a statement while a result
+	// set is still open on the same connection.
+	rows, err := q.Query(
+		`SELECT id, stock FROM warehouse_stock
+		 WHERE product_id = ? AND variant_id <=> ? AND stock > 0
+		 ORDER BY stock DESC, warehouse_id`,
+		productID, variantID)
+	if err != nil {
+		return err
+	}
+	type whRow struct {
+		id    int64
+		stock int
+	}
+	var entries []whRow
+	for rows.Next() {
+		var e whRow
+		if err := rows.Scan(&e.id, &e.stock); err != nil {
+			rows.Close()
+			return err
+		}
+		entries = append(entries, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	remaining := -delta
+	for _, e := range entries {
+		if remaining == 0 {
+			break
+		}
+		take := e.stock
+		if take > remaining {
+			take = remaining
+		}
+		if _, err := q.Exec("UPDATE warehouse_stock SET stock = stock - ? WHERE id = ?", take, e.id); err != nil {
+			return err
+		}
+		remaining -= take
+	}
+	// Any remainder means the warehouse breakdown was already behind the
+	// aggregate; the aggregate stays authoritative, so it is dropped.
+	return nil
+}
@@ -0,0 +1,388 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+)
+
+// errSubscriptionStock aborts a subscription run when a template line cannot
+// be fulfilled from current stock.
+var errSubscriptionStock = errors.New("stock tidak mencukupi")
+
+func registerSubscriptionRoutes(r *Router) {
+	r.POST("/api/v1/subscriptions", AuthMiddleware(CreateSubscription))
+	r.GET("/api/v1/subscriptions", AuthMiddleware(MySubscriptions))
+	r.PUT("/api/v1/subscriptions/:id", AuthMiddleware(UpdateSubscription))
+	r.POST("/api/v1/subscriptions/:id/pause", AuthMiddleware(PauseSubscription))
+	r.POST("/api/v1/subscriptions/:id/resume", AuthMiddleware(ResumeSubscription))
+	r.DELETE("/api/v1/subscriptions/:id", AuthMiddleware(CancelSubscription))
+}
+
+// Subscription is a recurring order template that the worker turns into real
+// orders every interval_days.
+type Subscription struct {
+	ID           int64              `json:"id"`
+	UserID       int64              `json:"user_id"`
+	IntervalDays int                `json:"interval_days"`
+	Status       string             `json:"status"`
+	NextRunAt    time.Time          `json:"next_run_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Items        []SubscriptionItem `json:"items,omitempty"`
+}
+
+// SubscriptionItem is one product line inside a subscription template.
+type SubscriptionItem struct {
+	ID             int64  `json:"id"`
+	SubscriptionID int64  `json:"subscription_id"`
+	ProductID      int64  `json:"product_id"`
+	VariantID      *int64 `json:"variant_id"`
+	Quantity       int    `json:"quantity"`
+}
+
+type subscriptionItemRequest struct {
+	ProductID int64  `json:"product_id"`
+	VariantID *int64 `json:"variant_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+// CreateSubscription registers a recurring order template for the user.
+func CreateSubscription(w http.ResponseWriter, r *http.Request) {
+	userID := currentUserID(r)
+	var req struct {
+		IntervalDays int                       `json:"interval_days"`
+		Items        []subscriptionItemRequest `json:"items"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.IntervalDays < 1 {
+		respondError(w, http.StatusBadRequest, "interval_days minimal 1")
+		return
+	}
+	if len(req.Items) == 0 {
+		respondError(w, http.StatusBadRequest, "subscription wajib punya minimal 1 item")
+		return
+	}
+	for _, it := range req.Items {
+		if it.Quantity < 1 {
+			respondError(w, http.StatusBadRequest, "quantity minimal 1")
+			return
+		}
+		if _, _, _, err := resolveItemPrice(it.ProductID, it.VariantID); err != nil {
+			if err == errVariantRequired {
+				respondError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+			return
+		}
+	}
+	nextRun := nowUTC().AddDate(0, 0, req.IntervalDays)
+	res, err := db.Exec(
+		"INSERT INTO subscriptions (user_id, interval_days, status, next_run_at, created_at) VALUES (?, ?, 'active', ?, ?)",
+		userID, req.IntervalDays, nextRun, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	for _, it := range req.Items {
+		if _, err := db.Exec(
+			"INSERT INTO subscription_items (subscription_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)",
+			id, it.ProductID, it.VariantID, it.Quantity); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{
+		"id":          id,
+		"next_run_at": nextRun,
+		"message":     "subscription berhasil dibuat",
+	})
+}
+
+const subscriptionColumns = "id, user_id, interval_days, status, next_run_at, created_at"
+
+func scanSubscription(row interface{ Scan(...any) error }) (Subscription, error) {
+	var s Subscription
+	err := row.Scan(&s.ID, &s.UserID, &s.IntervalDays, &s.Status, &s.NextRunAt, &s.CreatedAt)
+	return s, err
+}
+
+func fetchSubscriptionItems(subscriptionID int64) ([]SubscriptionItem, error) {
+	rows, err := db.Query(
+		"SELECT id, subscription_id, product_id, variant_id, quantity FROM subscription_items WHERE subscription_id = ?",
+		subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SubscriptionItem{}
+	for rows.Next() {
+		var (
+			it        SubscriptionItem
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.SubscriptionID, &it.ProductID, &variantID, &it.Quantity); err != nil {
+			return nil, err
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// MySubscriptions lists the authenticated user's subscriptions with items.
+func MySubscriptions(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		"SELECT "+subscriptionColumns+" FROM subscriptions WHERE user_id = ? ORDER BY created_at DESC",
+		currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	subs := []Subscription{}
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		s.Items, err = fetchSubscriptionItems(s.ID)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		subs = append(subs, s)
+	}
+	respondJSON(w, http.StatusOK, subs)
+}
+
+// loadOwnSubscription fetches a subscription and enforces ownership.
+func loadOwnSubscription(w http.ResponseWriter, r *http.Request) (Subscription, bool) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return Subscription{}, false
+	}
+	s, err := scanSubscription(db.QueryRow(
+		"SELECT "+subscriptionColumns+" FROM subscriptions WHERE id = ? AND user_id = ?",
+		id, currentUserID(r)))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "subscription tidak ditemukan")
+		return Subscription{}, false
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return Subscription{}, false
+	}
+	return s, true
+}
+
+// UpdateSubscription replaces a subscription's interval and items.
+func UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+	s, ok := loadOwnSubscription(w, r)
+	if !ok {
+		return
+	}
+	if s.Status == "cancelled" {
+		respondError(w, http.StatusBadRequest, "subscription sudah dibatalkan")
+		return
+	}
+	var req struct {
+		IntervalDays int                       `json:"interval_days"`
+		Items        []subscriptionItemRequest `json:"items"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.IntervalDays < 1 || len(req.Items) == 0 {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	for _, it := range req.Items {
+		if it.Quantity < 1 {
+			respondError(w, http.StatusBadRequest, "quantity minimal 1")
+			return
+		}
+	}
+	err := WithTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("UPDATE subscriptions SET interval_days = ? WHERE id = ?",
+			req.IntervalDays, s.ID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM subscription_items WHERE subscription_id = ?", s.ID); err != nil {
+			return err
+		}
+		for _, it := range req.Items {
+			if _, err := tx.Exec(
+				"INSERT INTO subscription_items (subscription_id, product_id, variant_id, quantity) VALUES (?, ?, ?, ?)",
+				s.ID, it.ProductID, it.VariantID, it.Quantity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "subscription berhasil diupdate")
+}
+
+// PauseSubscription stops the worker from running an active subscription.
+func PauseSubscription(w http.ResponseWriter, r *http.Request) {
+	s, ok := loadOwnSubscription(w, r)
+	if !ok {
+		return
+	}
+	if s.Status != "active" {
+		respondError(w, http.StatusBadRequest, "hanya subscription aktif yang bisa dipause")
+		return
+	}
+	if _, err := db.Exec("UPDATE subscriptions SET status = 'paused' WHERE id = ?", s.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "subscription dipause")
+}
+
+// ResumeSubscription reactivates a paused subscription and reschedules it.
+func ResumeSubscription(w http.ResponseWriter, r *http.Request) {
+	s, ok := loadOwnSubscription(w, r)
+	if !ok {
+		return
+	}
+	if s.Status != "paused" {
+		respondError(w, http.StatusBadRequest, "hanya subscription yang dipause yang bisa diresume")
+		return
+	}
+	nextRun := nowUTC().AddDate(0, 0, s.IntervalDays)
+	if _, err := db.Exec("UPDATE subscriptions SET status = 'active', next_run_at = ? WHERE id = ?",
+		nextRun, s.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "subscription diresume")
+}
+
+// CancelSubscription permanently stops a subscription.
+func CancelSubscription(w http.ResponseWriter, r *http.Request) {
+	s, ok := loadOwnSubscription(w, r)
+	if !ok {
+		return
+	}
+	if s.Status == "cancelled" {
+		respondError(w, http.StatusBadRequest, "subscription sudah dibatalkan")
+		return
+	}
+	if _, err := db.Exec("UPDATE subscriptions SET status = 'cancelled' WHERE id = ?", s.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "subscription dibatalkan")
+}
+
+// StartSubscriptionWorker runs due subscriptions every hour.
+func StartSubscriptionWorker() {
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			runDueSubscriptions()
+		}
+	}()
+}
+
+// runDueSubscriptions turns every due active subscription into a paid order at
+// current catalog prices, decrementing stock directly (no reservation timer or
+// hearts involved).
+func runDueSubscriptions() {
+	rows, err := db.Query(
+		"SELECT "+subscriptionColumns+" FROM subscriptions WHERE status = 'active' AND next_run_at <= ?", nowUTC())
+	if err != nil {
+		log.Printf("gagal ambil subscription jatuh tempo: %v", err)
+		return
+	}
+	var due []Subscription
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			rows.Close()
+			log.Printf("gagal scan subscription: %v", err)
+			return
+		}
+		due = append(due, s)
+	}
+	rows.Close()
+	for _, s := range due {
+		if err := runSubscription(s); err != nil {
+			log.Printf("gagal menjalankan subscription %d: %v", s.ID, err)
+			createNotification(s.UserID, "Subscription gagal diproses",
+				"Order berulang kamu gagal dibuat: "+err.Error()+". Akan dicoba lagi periode berikutnya.")
+		}
+		nextRun := nowUTC().AddDate(0, 0, s.IntervalDays)
+		if _, err := db.Exec("UPDATE subscriptions SET next_run_at = ? WHERE id = ?",
+			nextRun, s.ID); err != nil {
+			log.Printf("gagal update jadwal subscription %d: %v", s.ID, err)
+		}
+	}
+}
+
+// runSubscription creates one order from a subscription template.
+func runSubscription(s Subscription) error {
+	items, err := fetchSubscriptionItems(s.ID)
+	if err != nil {
+		return err
+	}
+	type line struct {
+		item  SubscriptionItem
+		price float64
+	}
+	var (
+		lines []line
+		total float64
+	)
+	for _, it := range items {
+		price, stock, _, err := resolveItemPrice(it.ProductID, it.VariantID)
+		if err != nil {
+			return err
+		}
+		if stock < it.Quantity {
+			return errSubscriptionStock
+		}
+		lines = append(lines, line{item: it, price: price})
+		total += price * float64(it.Quantity)
+	}
+	var orderID int64
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"INSERT INTO orders (user_id, total_price, status, created_at) VALUES (?, ?, 'paid', ?)",
+			s.UserID, total, nowUTC())
+		if err != nil {
+			return err
+		}
+		orderID, _ = res.LastInsertId()
+		for _, l := range lines {
+			if _, err := tx.Exec(
+				"INSERT INTO order_items (order_id, product_id, variant_id, quantity, price) VALUES (?, ?, ?, ?, ?)",
+				orderID, l.item.ProductID, l.item.VariantID, l.item.Quantity, l.price); err != nil {
+				return err
+			}
+			if err := stockSvc.Reserve(tx, l.item.ProductID, l.item.VariantID, l.item.Quantity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	assignLicenseKeys(orderID)
+	createNotification(s.UserID, "Order berulang dibuat",
+		"Order dari subscription kamu berhasil dibuat dan dibayar otomatis.")
+	return nil
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+func registerSupplierFeedRoutes(r *Router) {
+	r.GET("/api/v1/admin/supplier-feeds", RoleMiddleware("admin")(GetSupplierFeeds))
+	r.POST("/api/v1/admin/supplier-feeds", RoleMiddleware("admin")(CreateSupplierFeed))
+	r.POST("/api/v1/admin/supplier-feeds/:id/run", RoleMiddleware("admin")(RunSupplierFeed))
+	r.GET("/api/v1/admin/supplier-feeds/:id/runs", RoleMiddleware("admin")(GetSupplierFeedRuns))
+}
+
+// SupplierFeed describes a supplier catalog pulled over HTTP on a schedule.
+// Feed rows are matched to products by barcode; the update flags control
+// which fields the feed may touch.
+type SupplierFeed struct {
+	ID              int64     `json:"id"`
+	Name            string    `json:"name"`
+	URL             string    `json:"url"`
+	Format          string    `json:"format"`
+	UpdatePrices    bool      `json:"update_prices"`
+	UpdateStock     bool      `json:"update_stock"`
+	CreateMissing   bool      `json:"create_missing"`
+	CategoryID      *int64    `json:"category_id"`
+	IntervalMinutes int       `json:"interval_minutes"`
+	Enabled         bool      `json:"enabled"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+const supplierFeedColumns = "id, name, url, format, update_prices, update_stock, create_missing, category_id, interval_minutes, enabled, created_at"
+
+func scanSupplierFeed(row interface{ Scan(...any) error }) (SupplierFeed, error) {
+	var (
+		f          SupplierFeed
+		categoryID sql.NullInt64
+	)
+	err := row.Scan(&f.ID, &f.Name, &f.URL, &f.Format, &f.UpdatePrices, &f.UpdateStock,
+		&f.CreateMissing, &categoryID, &f.IntervalMinutes, &f.Enabled, &f.CreatedAt)
+	if categoryID.Valid {
+		f.CategoryID = &categoryID.Int64
+	}
+	return f, err
+}
+
+// feedItem is one row of a supplier feed after parsing.
+type feedItem struct {
+	Barcode string  `json:"barcode"`
+	Name    string  `json:"name"`
+	Price   float64 `json:"price"`
+	Stock   int     `json:"stock"`
+}
+
+// parseFeed decodes a feed body. CSV expects a barcode,name,price,stock
+// header; JSON expects an array of objects with the same fields.
+func parseFeed(format string, body io.Reader) ([]feedItem, error) {
+	switch format {
+	case "csv":
+		reader := csv.NewReader(body)
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+		var items []feedItem
+		for i, rec := range records {
+			if i == 0 || len(rec) < 4 {
+				// Skip the header and short rows.
+				continue
+			}
+			price, err1 := strconv.ParseFloat(rec[2], 64)
+			stock, err2 := strconv.Atoi(rec[3])
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			items = append(items, feedItem{Barcode: rec[0], Name: rec[1], Price: price, Stock: stock})
+		}
+		return items, nil
+	case "json":
+		var items []feedItem
+		if err := json.NewDecoder(body).Decode(&items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("format feed %s tidak dikenal", format)
+	}
+}
+
+// syncSupplierFeed pulls one feed and applies it, recording the run with its
+// created/updated/skipped counts.
+func syncSupplierFeed(feed SupplierFeed) {
+	started := nowUTC()
+	var (
+		created, updated, skipped int
+		runErr                    error
+	)
+	defer func() {
+		errText := sql.NullString{}
+		if runErr != nil {
+			errText = sql.NullString{String: runErr.Error(), Valid: true}
+			log.Printf("feed %s gagal: %v", feed.Name, runErr)
+		}
+		if _, err := db.Exec(
+			`INSERT INTO feed_runs (feed_id, started_at, finished_at, created_count, updated_count, skipped_count, error)
+			 VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			feed.ID, started, nowUTC(), created, updated, skipped, errText); err != nil {
+			log.Printf("gagal mencatat run feed %d: %v", feed.ID, err)
+		}
+	}()
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(feed.URL)
+	if err != nil {
+		runErr = err
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		runErr = fmt.Errorf("supplier menjawab status %d", resp.StatusCode)
+		return
+	}
+	items, err := parseFeed(feed.Format, resp.Body)
+	if err != nil {
+		runErr = err
+		return
+	}
+
+	for _, item := range items {
+		if item.Barcode == "" {
+			skipped++
+			continue
+		}
+		var (
+			productID int64
+			stock     int
+		)
+		err := db.QueryRow("SELECT id, stock FROM products WHERE barcode = ?", item.Barcode).
+			Scan(&productID, &stock)
+		if err == sql.ErrNoRows {
+			if !feed.CreateMissing || feed.CategoryID == nil || item.Name == "" {
+				skipped++
+				continue
+			}
+			if _, err := db.Exec(
+				`INSERT INTO products (category_id, name, description, barcode, price, stock, unit, created_at, updated_at)
+				 VALUES (?, ?, '', ?, ?, ?, 'pcs', ?, ?)`,
+				*feed.CategoryID, item.Name, item.Barcode, item.Price, item.Stock, nowUTC(), nowUTC()); err != nil {
+				log.Printf("feed %s gagal membuat produk %s: %v", feed.Name, item.Barcode, err)
+				skipped++
+				continue
+			}
+			created++
+			continue
+		} else if err != nil {
+			log.Printf("feed %s gagal membaca produk %s: %v", feed.Name, item.Barcode, err)
+			skipped++
+			continue
+		}
+		touched := false
+		if feed.UpdatePrices {
+			if _, err := db.Exec("UPDATE products SET price = ?, updated_at = ? WHERE id = ?",
+				item.Price, nowUTC(), productID); err != nil {
+				log.Printf("feed %s gagal update harga produk %d: %v", feed.Name, productID, err)
+				skipped++
+				continue
+			}
+			touched = true
+		}
+		if feed.UpdateStock {
+			if delta := item.Stock - stock; delta != 0 {
+				if err := applyStockDelta(productID, nil, delta, "feed_sync", feed.ID, 0); err != nil {
+					log.Printf("feed %s gagal update stock produk %d: %v", feed.Name, productID, err)
+					skipped++
+					continue
+				}
+			}
+			touched = true
+		}
+		if touched {
+			updated++
+		} else {
+			skipped++
+		}
+	}
+}
+
+// StartFeedSyncWorker periodically runs every enabled feed whose interval has
+// elapsed since its last run.
+func StartFeedSyncWorker() {
+	go func() {
+		for range time.Tick(time.Minute) {
+			rows, err := db.Query(
+				`SELECT `+supplierFeedColumns+` FROM supplier_feeds f
+				 WHERE enabled = 1 AND NOT EXISTS (
+					SELECT 1 FROM feed_runs r
+					WHERE r.feed_id = f.id AND r.started_at > DATE_SUB(?, INTERVAL f.interval_minutes MINUTE)
+				 )`, nowUTC())
+			if err != nil {
+				log.Printf("gagal cek feed yang due: %v", err)
+				continue
+			}
+			var due []SupplierFeed
+			for rows.Next() {
+				f, err := scanSupplierFeed(rows)
+				if err != nil {
+					log.Printf("gagal scan feed: %v", err)
+					break
+				}
+				due = append(due, f)
+			}
+			rows.Close()
+			for _, f := range due {
+				syncSupplierFeed(f)
+			}
+		}
+	}()
+}
+
+// GetSupplierFeeds lists the configured feeds.
+func GetSupplierFeeds(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT " + supplierFeedColumns + " FROM supplier_feeds ORDER BY id")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	feeds := []SupplierFeed{}
+	for rows.Next() {
+		f, err := scanSupplierFeed(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		feeds = append(feeds, f)
+	}
+	respondJSON(w, http.StatusOK, feeds)
+}
+
+// CreateSupplierFeed registers a feed to sync from.
+func CreateSupplierFeed(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name            string `json:"name"`
+		URL             string `json:"url"`
+		Format          string `json:"format"`
+		UpdatePrices    bool   `json:"update_prices"`
+		UpdateStock     bool   `json:"update_stock"`
+		CreateMissing   bool   `json:"create_missing"`
+		CategoryID      *int64 `json:"category_id"`
+		IntervalMinutes int    `json:"interval_minutes"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Name == "" || req.URL == "" {
+		respondError(w, http.StatusBadRequest, "name dan url wajib diisi")
+		return
+	}
+	if req.Format != "csv" && req.Format != "json" {
+		respondError(w, http.StatusBadRequest, "format harus csv atau json")
+		return
+	}
+	if req.IntervalMinutes < 1 {
+		respondError(w, http.StatusBadRequest, "interval_minutes minimal 1")
+		return
+	}
+	if req.CreateMissing && req.CategoryID == nil {
+		respondError(w, http.StatusBadRequest, "category_id wajib diisi jika create_missing aktif")
+		return
+	}
+	res, err := db.Exec(
+		`INSERT INTO supplier_feeds (name, url, format, update_prices, update_stock, create_missing, category_id, interval_minutes, enabled, created_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?, 1, ?)`,
+		req.Name, req.URL, req.Format, req.UpdatePrices, req.UpdateStock, req.CreateMissing,
+		req.CategoryID, req.IntervalMinutes, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "feed supplier didaftarkan"})
+}
+
+// RunSupplierFeed triggers one sync immediately.
+func RunSupplierFeed(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	feed, err := scanSupplierFeed(db.QueryRow(
+		"SELECT "+supplierFeedColumns+" FROM supplier_feeds WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "feed tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	go syncSupplierFeed(feed)
+	respondMessage(w, http.StatusAccepted, "sync feed dijalankan")
+}
+
+// GetSupplierFeedRuns lists the recent runs of one feed with their counts.
+func GetSupplierFeedRuns(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	rows, err := db.Query(
+		`SELECT id, started_at, finished_at, created_count, updated_count, skipped_count, COALESCE(error, '')
+		 FROM feed_runs WHERE feed_id = ? ORDER BY id DESC LIMIT 50`, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	type feedRun struct {
+		ID         int64     `json:"id"`
+		StartedAt  time.Time `json:"started_at"`
+		FinishedAt time.Time `json:"finished_at"`
+		Created    int       `json:"created"`
+		Updated    int       `json:"updated"`
+		Skipped    int       `json:"skipped"`
+		Error      string    `json:"error,omitempty"`
+	}
+	runs := []feedRun{}
+	for rows.Next() {
+		var fr feedRun
+		if err := rows.Scan(&fr.ID, &fr.StartedAt, &fr.FinishedAt, &fr.Created, &fr.Updated, &fr.Skipped, &fr.Error); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		runs = append(runs, fr)
+	}
+	respondJSON(w, http.StatusOK, runs)
+}
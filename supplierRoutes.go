@@ -0,0 +1,452 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func registerSupplierRoutes(r *Router) {
+	r.GET("/api/v1/suppliers", RoleMiddleware("admin", "stocker")(GetSuppliers))
+	r.POST("/api/v1/suppliers", RoleMiddleware("admin", "stocker")(CreateSupplier))
+	r.PUT("/api/v1/suppliers/:id", RoleMiddleware("admin", "stocker")(UpdateSupplier))
+	r.DELETE("/api/v1/suppliers/:id", RoleMiddleware("admin")(DeleteSupplier))
+	r.GET("/api/v1/purchase-orders", RoleMiddleware("admin", "stocker")(GetPurchaseOrders))
+	r.GET("/api/v1/purchase-orders/:id", RoleMiddleware("admin", "stocker")(GetPurchaseOrderByID))
+	r.POST("/api/v1/purchase-orders", RoleMiddleware("admin", "stocker")(CreatePurchaseOrder))
+	r.POST("/api/v1/purchase-orders/:id/order", RoleMiddleware("admin", "stocker")(MarkPurchaseOrderOrdered))
+	r.POST("/api/v1/purchase-orders/:id/cancel", RoleMiddleware("admin", "stocker")(CancelPurchaseOrder))
+	r.POST("/api/v1/purchase-orders/:id/receive", RoleMiddleware("admin", "stocker")(ReceivePurchaseOrder))
+}
+
+// Supplier is a vendor that incoming inventory is ordered from.
+type Supplier struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	ContactName string    `json:"contact_name"`
+	Phone       string    `json:"phone"`
+	Email       string    `json:"email"`
+	Address     string    `json:"address"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PurchaseOrder plans incoming inventory from one supplier.
+// Status flow: draft -> ordered -> partial -> received, or cancelled.
+type PurchaseOrder struct {
+	ID             int64               `json:"id"`
+	SupplierID     int64               `json:"supplier_id"`
+	Status         string              `json:"status"`
+	ExpectedAt     *time.Time          `json:"expected_at"`
+	Notes          string              `json:"notes"`
+	HasDiscrepancy bool                `json:"has_discrepancy"`
+	CreatedBy      int64               `json:"created_by"`
+	CreatedAt      time.Time           `json:"created_at"`
+	UpdatedAt      time.Time           `json:"updated_at"`
+	Items          []PurchaseOrderItem `json:"items,omitempty"`
+}
+
+// PurchaseOrderItem is one product line on a purchase order.
+type PurchaseOrderItem struct {
+	ID               int64   `json:"id"`
+	PurchaseOrderID  int64   `json:"purchase_order_id"`
+	ProductID        int64   `json:"product_id"`
+	VariantID        *int64  `json:"variant_id"`
+	QuantityOrdered  int     `json:"quantity_ordered"`
+	QuantityReceived int     `json:"quantity_received"`
+	UnitCost         float64 `json:"unit_cost"`
+}
+
+type supplierRequest struct {
+	Name        string `json:"name"`
+	ContactName string `json:"contact_name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+	Address     string `json:"address"`
+}
+
+// GetSuppliers lists every supplier.
+func GetSuppliers(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		"SELECT id, name, contact_name, phone, email, address, created_at FROM suppliers")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	suppliers := []Supplier{}
+	for rows.Next() {
+		var s Supplier
+		if err := rows.Scan(&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email,
+			&s.Address, &s.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		suppliers = append(suppliers, s)
+	}
+	respondJSON(w, http.StatusOK, suppliers)
+}
+
+// CreateSupplier inserts a supplier.
+func CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	var req supplierRequest
+	if err := decodeBody(r, &req); err != nil || req.Name == "" {
+		respondError(w, http.StatusBadRequest, "nama supplier wajib diisi")
+		return
+	}
+	res, err := db.Exec(
+		"INSERT INTO suppliers (name, contact_name, phone, email, address, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		req.Name, req.ContactName, req.Phone, req.Email, req.Address, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "supplier berhasil dibuat"})
+}
+
+// UpdateSupplier overwrites an existing supplier.
+func UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req supplierRequest
+	if err := decodeBody(r, &req); err != nil || req.Name == "" {
+		respondError(w, http.StatusBadRequest, "nama supplier wajib diisi")
+		return
+	}
+	res, err := db.Exec(
+		"UPDATE suppliers SET name = ?, contact_name = ?, phone = ?, email = ?, address = ? WHERE id = ?",
+		req.Name, req.ContactName, req.Phone, req.Email, req.Address, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "supplier tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "supplier berhasil diupdate")
+}
+
+// DeleteSupplier removes a supplier that has no purchase orders.
+func DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM purchase_orders WHERE supplier_id = ?", id).
+		Scan(&count); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if count > 0 {
+		respondError(w, http.StatusBadRequest, "supplier masih punya purchase order")
+		return
+	}
+	res, err := db.Exec("DELETE FROM suppliers WHERE id = ?", id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "supplier tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "supplier berhasil dihapus")
+}
+
+const purchaseOrderColumns = "id, supplier_id, status, expected_at, notes, has_discrepancy, created_by, created_at, updated_at"
+
+func scanPurchaseOrder(row interface{ Scan(...any) error }) (PurchaseOrder, error) {
+	var (
+		po       PurchaseOrder
+		expected sql.NullTime
+	)
+	err := row.Scan(&po.ID, &po.SupplierID, &po.Status, &expected, &po.Notes,
+		&po.HasDiscrepancy, &po.CreatedBy, &po.CreatedAt, &po.UpdatedAt)
+	if expected.Valid {
+		po.ExpectedAt = &expected.Time
+	}
+	return po, err
+}
+
+func fetchPurchaseOrderItems(poID int64) ([]PurchaseOrderItem, error) {
+	rows, err := db.Query(
+		`SELECT id, purchase_order_id, product_id, variant_id, quantity_ordered, quantity_received, unit_cost
+		 FROM purchase_order_items WHERE purchase_order_id = ?`, poID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []PurchaseOrderItem{}
+	for rows.Next() {
+		var (
+			it        PurchaseOrderItem
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.PurchaseOrderID, &it.ProductID, &variantID,
+			&it.QuantityOrdered, &it.QuantityReceived, &it.UnitCost); err != nil {
+			return nil, err
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		items = append(items, it)
+	}
+	return items, rows.Err()
+}
+
+// GetPurchaseOrders lists purchase orders, optionally filtered by ?status=.
+func GetPurchaseOrders(w http.ResponseWriter, r *http.Request) {
+	query := "SELECT " + purchaseOrderColumns + " FROM purchase_orders"
+	args := []any{}
+	if status := r.URL.Query().Get("status"); status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at DESC"
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	orders := []PurchaseOrder{}
+	for rows.Next() {
+		po, err := scanPurchaseOrder(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		orders = append(orders, po)
+	}
+	respondJSON(w, http.StatusOK, orders)
+}
+
+// GetPurchaseOrderByID returns one purchase order with its lines.
+func GetPurchaseOrderByID(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	po, err := scanPurchaseOrder(db.QueryRow(
+		"SELECT "+purchaseOrderColumns+" FROM purchase_orders WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "purchase order tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	po.Items, err = fetchPurchaseOrderItems(po.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, po)
+}
+
+// CreatePurchaseOrder inserts a draft purchase order with its lines.
+func CreatePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SupplierID int64      `json:"supplier_id"`
+		ExpectedAt *time.Time `json:"expected_at"`
+		Notes      string     `json:"notes"`
+		Items      []struct {
+			ProductID int64   `json:"product_id"`
+			VariantID *int64  `json:"variant_id"`
+			Quantity  int     `json:"quantity"`
+			UnitCost  float64 `json:"unit_cost"`
+		} `json:"items"`
+	}
+	if err := decodeBody(r, &req); err != nil || len(req.Items) == 0 {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM suppliers WHERE id = ?", req.SupplierID).
+		Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists == 0 {
+		respondError(w, http.StatusNotFound, "supplier tidak ditemukan")
+		return
+	}
+	for _, it := range req.Items {
+		if it.Quantity < 1 {
+			respondError(w, http.StatusBadRequest, "quantity minimal 1")
+			return
+		}
+	}
+	now := nowUTC()
+	res, err := db.Exec(
+		`INSERT INTO purchase_orders (supplier_id, status, expected_at, notes, created_by, created_at, updated_at)
+		 VALUES (?, 'draft', ?, ?, ?, ?, ?)`,
+		req.SupplierID, req.ExpectedAt, req.Notes, currentUserID(r), now, now)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	for _, it := range req.Items {
+		if _, err := db.Exec(
+			`INSERT INTO purchase_order_items (purchase_order_id, product_id, variant_id, quantity_ordered, quantity_received, unit_cost)
+			 VALUES (?, ?, ?, ?, 0, ?)`,
+			id, it.ProductID, it.VariantID, it.Quantity, it.UnitCost); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "purchase order berhasil dibuat"})
+}
+
+// loadPurchaseOrder fetches a PO by the :id path param.
+func loadPurchaseOrder(w http.ResponseWriter, r *http.Request) (PurchaseOrder, bool) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return PurchaseOrder{}, false
+	}
+	po, err := scanPurchaseOrder(db.QueryRow(
+		"SELECT "+purchaseOrderColumns+" FROM purchase_orders WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "purchase order tidak ditemukan")
+		return PurchaseOrder{}, false
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return PurchaseOrder{}, false
+	}
+	return po, true
+}
+
+// MarkPurchaseOrderOrdered flips a draft PO to ordered once it is sent to the supplier.
+func MarkPurchaseOrderOrdered(w http.ResponseWriter, r *http.Request) {
+	po, ok := loadPurchaseOrder(w, r)
+	if !ok {
+		return
+	}
+	if po.Status != "draft" {
+		respondError(w, http.StatusBadRequest, "hanya purchase order draft yang bisa diorder")
+		return
+	}
+	if _, err := db.Exec("UPDATE purchase_orders SET status = 'ordered', updated_at = ? WHERE id = ?",
+		nowUTC(), po.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "purchase order ditandai ordered")
+}
+
+// ReceivePurchaseOrder records delivered quantities against PO lines,
+// increments stock through the ledger, flags discrepancies, and closes the PO
+// once every line is fully received.
+func ReceivePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	po, ok := loadPurchaseOrder(w, r)
+	if !ok {
+		return
+	}
+	if po.Status != "ordered" && po.Status != "partial" {
+		respondError(w, http.StatusBadRequest, "purchase order belum diorder atau sudah selesai")
+		return
+	}
+	var req struct {
+		Items []struct {
+			ItemID   int64 `json:"item_id"`
+			Quantity int   `json:"quantity"`
+		} `json:"items"`
+	}
+	if err := decodeBody(r, &req); err != nil || len(req.Items) == 0 {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	items, err := fetchPurchaseOrderItems(po.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	byID := map[int64]PurchaseOrderItem{}
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	actorID := currentUserID(r)
+	discrepancies := []string{}
+	for _, rec := range req.Items {
+		if rec.Quantity < 1 {
+			respondError(w, http.StatusBadRequest, "quantity minimal 1")
+			return
+		}
+		it, found := byID[rec.ItemID]
+		if !found {
+			respondError(w, http.StatusNotFound, "item purchase order tidak ditemukan")
+			return
+		}
+		if _, err := db.Exec(
+			"UPDATE purchase_order_items SET quantity_received = quantity_received + ? WHERE id = ?",
+			rec.Quantity, rec.ItemID); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if err := applyStockDelta(it.ProductID, it.VariantID, rec.Quantity,
+			"po_receive", po.ID, actorID); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if it.QuantityReceived+rec.Quantity > it.QuantityOrdered {
+			discrepancies = append(discrepancies, fmt.Sprintf(
+				"item %d diterima %d dari %d yang diorder",
+				it.ID, it.QuantityReceived+rec.Quantity, it.QuantityOrdered))
+		}
+	}
+	items, err = fetchPurchaseOrderItems(po.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	complete := true
+	for _, it := range items {
+		if it.QuantityReceived < it.QuantityOrdered {
+			complete = false
+			break
+		}
+	}
+	status := "partial"
+	if complete {
+		status = "received"
+	}
+	if _, err := db.Exec(
+		"UPDATE purchase_orders SET status = ?, has_discrepancy = has_discrepancy OR ?, updated_at = ? WHERE id = ?",
+		status, len(discrepancies) > 0, nowUTC(), po.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"status":        status,
+		"discrepancies": discrepancies,
+		"message":       "penerimaan barang berhasil dicatat",
+	})
+}
+
+// CancelPurchaseOrder cancels a PO that has not received any goods yet.
+func CancelPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	po, ok := loadPurchaseOrder(w, r)
+	if !ok {
+		return
+	}
+	if po.Status != "draft" && po.Status != "ordered" {
+		respondError(w, http.StatusBadRequest, "purchase order ini sudah tidak bisa dibatalkan")
+		return
+	}
+	if _, err := db.Exec("UPDATE purchase_orders SET status = 'cancelled', updated_at = ? WHERE id = ?",
+		nowUTC(), po.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "purchase order dibatalkan")
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+)
+
+func registerTicketRoutes(r *Router) {
+	r.POST("/api/v1/tickets", AuthMiddleware(CreateTicket))
+	r.GET("/api/v1/tickets", AuthMiddleware(MyTickets))
+	r.GET("/api/v1/tickets/:id", AuthMiddleware(GetTicketByID))
+	r.POST("/api/v1/tickets/:id/messages", AuthMiddleware(CreateTicketMessage))
+	r.POST("/api/v1/tickets/:id/close", AuthMiddleware(CloseTicket))
+	r.GET("/api/v1/admin/tickets", RoleMiddleware("admin", "stocker", "cashier")(GetAllTickets))
+}
+
+// Ticket is a support conversation opened by a user, optionally tied to an order.
+type Ticket struct {
+	ID        int64           `json:"id"`
+	UserID    int64           `json:"user_id"`
+	OrderID   *int64          `json:"order_id"`
+	Subject   string          `json:"subject"`
+	Category  string          `json:"category"`
+	Status    string          `json:"status"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Messages  []TicketMessage `json:"messages,omitempty"`
+}
+
+// TicketMessage is one entry in a ticket's thread.
+type TicketMessage struct {
+	ID         int64     `json:"id"`
+	TicketID   int64     `json:"ticket_id"`
+	SenderRole string    `json:"sender_role"`
+	SenderID   int64     `json:"sender_id"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+const ticketColumns = "id, user_id, order_id, subject, category, status, created_at, updated_at"
+
+func scanTicket(row interface{ Scan(...any) error }) (Ticket, error) {
+	var (
+		t       Ticket
+		orderID sql.NullInt64
+	)
+	err := row.Scan(&t.ID, &t.UserID, &orderID, &t.Subject, &t.Category, &t.Status,
+		&t.CreatedAt, &t.UpdatedAt)
+	if orderID.Valid {
+		t.OrderID = &orderID.Int64
+	}
+	return t, err
+}
+
+func isStaff(role string) bool {
+	return role == "admin" || role == "stocker" || role == "cashier"
+}
+
+// CreateTicket opens a ticket with its first message.
+func CreateTicket(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Subject  string `json:"subject"`
+		Category string `json:"category"`
+		OrderID  *int64 `json:"order_id"`
+		Message  string `json:"message"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	if req.Subject == "" || req.Message == "" {
+		respondError(w, http.StatusBadRequest, "subject dan message wajib diisi")
+		return
+	}
+	userID := currentUserID(r)
+	if req.OrderID != nil {
+		var exists int
+		if err := db.QueryRow("SELECT COUNT(*) FROM orders WHERE id = ? AND user_id = ?",
+			*req.OrderID, userID).Scan(&exists); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if exists == 0 {
+			respondError(w, http.StatusNotFound, "order tidak ditemukan")
+			return
+		}
+	}
+	now := nowUTC()
+	res, err := db.Exec(
+		"INSERT INTO tickets (user_id, order_id, subject, category, status, created_at, updated_at) VALUES (?, ?, ?, ?, 'open', ?, ?)",
+		userID, req.OrderID, req.Subject, req.Category, now, now)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	if _, err := db.Exec(
+		"INSERT INTO ticket_messages (ticket_id, sender_role, sender_id, message, created_at) VALUES (?, 'user', ?, ?, ?)",
+		id, userID, req.Message, now); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "ticket berhasil dibuat"})
+}
+
+// MyTickets lists the authenticated user's tickets.
+func MyTickets(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		"SELECT "+ticketColumns+" FROM tickets WHERE user_id = ? ORDER BY updated_at DESC",
+		currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	tickets := []Ticket{}
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		tickets = append(tickets, t)
+	}
+	respondJSON(w, http.StatusOK, tickets)
+}
+
+// GetAllTickets lists every ticket for staff.
+func GetAllTickets(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT " + ticketColumns + " FROM tickets ORDER BY updated_at DESC")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	tickets := []Ticket{}
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		tickets = append(tickets, t)
+	}
+	respondJSON(w, http.StatusOK, tickets)
+}
+
+// loadTicketForRequest fetches a ticket and checks the caller may access it.
+func loadTicketForRequest(r *http.Request) (*Ticket, int, string) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		return nil, http.StatusBadRequest, "id tidak valid"
+	}
+	t, err := scanTicket(db.QueryRow("SELECT "+ticketColumns+" FROM tickets WHERE id = ?", id))
+	if err == sql.ErrNoRows {
+		return nil, http.StatusNotFound, "ticket tidak ditemukan"
+	} else if err != nil {
+		return nil, http.StatusInternalServerError, err.Error()
+	}
+	claims := currentClaims(r)
+	if !isStaff(claims.Role) && t.UserID != claims.ID {
+		return nil, http.StatusNotFound, "ticket tidak ditemukan"
+	}
+	return &t, 0, ""
+}
+
+// GetTicketByID returns one ticket with its full message thread.
+func GetTicketByID(w http.ResponseWriter, r *http.Request) {
+	t, status, msg := loadTicketForRequest(r)
+	if t == nil {
+		respondError(w, status, msg)
+		return
+	}
+	rows, err := db.Query(
+		"SELECT id, ticket_id, sender_role, sender_id, message, created_at FROM ticket_messages WHERE ticket_id = ? ORDER BY created_at",
+		t.ID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	t.Messages = []TicketMessage{}
+	for rows.Next() {
+		var m TicketMessage
+		if err := rows.Scan(&m.ID, &m.TicketID, &m.SenderRole, &m.SenderID, &m.Message, &m.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		t.Messages = append(t.Messages, m)
+	}
+	respondJSON(w, http.StatusOK, t)
+}
+
+// CreateTicketMessage appends to the thread; staff replies mark the ticket answered.
+func CreateTicketMessage(w http.ResponseWriter, r *http.Request) {
+	t, status, msg := loadTicketForRequest(r)
+	if t == nil {
+		respondError(w, status, msg)
+		return
+	}
+	if t.Status == "closed" {
+		respondError(w, http.StatusBadRequest, "ticket sudah ditutup")
+		return
+	}
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Message == "" {
+		respondError(w, http.StatusBadRequest, "message wajib diisi")
+		return
+	}
+	claims := currentClaims(r)
+	if _, err := db.Exec(
+		"INSERT INTO ticket_messages (ticket_id, sender_role, sender_id, message, created_at) VALUES (?, ?, ?, ?, ?)",
+		t.ID, claims.Role, claims.ID, req.Message, nowUTC()); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	newStatus := "open"
+	if isStaff(claims.Role) {
+		newStatus = "answered"
+	}
+	if _, err := db.Exec("UPDATE tickets SET status = ?, updated_at = ? WHERE id = ?",
+		newStatus, nowUTC(), t.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if isStaff(claims.Role) {
+		createNotification(t.UserID, "Ticket dijawab",
+			"Ticket \""+t.Subject+"\" kamu sudah dijawab. Cek balasannya.")
+	}
+	respondMessage(w, http.StatusCreated, "pesan berhasil dikirim")
+}
+
+// CloseTicket marks a ticket closed.
+func CloseTicket(w http.ResponseWriter, r *http.Request) {
+	t, status, msg := loadTicketForRequest(r)
+	if t == nil {
+		respondError(w, status, msg)
+		return
+	}
+	if t.Status == "closed" {
+		respondError(w, http.StatusBadRequest, "ticket sudah ditutup")
+		return
+	}
+	if _, err := db.Exec("UPDATE tickets SET status = 'closed', updated_at = ? WHERE id = ?",
+		nowUTC(), t.ID); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "ticket berhasil ditutup")
+}
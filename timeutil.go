@@ -0,0 +1,34 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// displayLocation is the timezone used when rendering timestamps for humans
+// (receipts, notifications). Storage and comparisons always happen in UTC.
+var displayLocation = time.UTC
+
+// loadDisplayLocation resolves config.DisplayTZ, falling back to UTC when the
+// name is unknown so a typo in the environment never takes the server down.
+func loadDisplayLocation() {
+	loc, err := time.LoadLocation(config.DisplayTZ)
+	if err != nil {
+		log.Printf("timezone %q tidak dikenal, pakai UTC: %v", config.DisplayTZ, err)
+		loc = time.UTC
+	}
+	displayLocation = loc
+}
+
+// nowUTC is the single source of "now" for anything stored in or compared
+// against the database. The DSN pins the session to UTC, so mixing this with
+// column values is safe.
+func nowUTC() time.Time {
+	return time.Now().UTC()
+}
+
+// displayTime renders a timestamp as RFC3339 in the configured display
+// timezone.
+func displayTime(t time.Time) string {
+	return t.In(displayLocation).Format(time.RFC3339)
+}
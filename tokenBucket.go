@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenBucket is an in-memory token-bucket limiter. Unlike ipThrottle's
+// sliding window it refills continuously, so well-behaved clients never see a
+// hard cliff at the window edge. Process-local on purpose, same as ipThrottle.
+type tokenBucket struct {
+	mu      sync.Mutex
+	buckets map[string]*bucketState
+	rate    float64 // tokens added per second
+	burst   float64
+}
+
+type bucketState struct {
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perMinute, burst int) *tokenBucket {
+	return &tokenBucket{
+		buckets: map[string]*bucketState{},
+		rate:    float64(perMinute) / 60,
+		burst:   float64(burst),
+	}
+}
+
+// take spends one token for key. When the bucket is empty it reports how long
+// the caller has to wait for the next token.
+func (b *tokenBucket) take(key string) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	st, ok := b.buckets[key]
+	if !ok {
+		st = &bucketState{tokens: b.burst, last: now}
+		b.buckets[key] = st
+	}
+	st.tokens = math.Min(b.burst, st.tokens+now.Sub(st.last).Seconds()*b.rate)
+	st.last = now
+	if st.tokens < 1 {
+		return false, time.Duration((1 - st.tokens) / b.rate * float64(time.Second))
+	}
+	st.tokens--
+	return true, 0
+}
+
+// writeLimiter guards mutating endpoints (register, login, cart and order
+// writes); the budget is configurable via RATE_LIMIT_PER_MINUTE/RATE_LIMIT_BURST.
+var writeLimiter *tokenBucket
+
+func initWriteLimiter() {
+	writeLimiter = newTokenBucket(config.RateLimitPerMinute, config.RateLimitBurst)
+}
+
+// RateLimitMiddleware enforces the write budget per authenticated account, or
+// per source IP on public endpoints. Rejections carry a Retry-After header so
+// clients can back off precisely.
+func RateLimitMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := "ip:" + clientIP(r)
+		if claims := currentClaims(r); claims != nil {
+			key = fmt.Sprintf("%s:%d", claims.Role, claims.ID)
+		}
+		ok, retryAfter := writeLimiter.take(key)
+		if !ok {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(math.Ceil(retryAfter.Seconds()))))
+			respondError(w, http.StatusTooManyRequests, "terlalu banyak request, coba lagi nanti")
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+func registerTwoFactorRoutes(r *Router) {
+	staff := RoleMiddleware("admin", "stocker", "cashier", "driver")
+	r.POST("/api/v1/me/2fa/enroll", staff(EnrollTwoFactor))
+	r.POST("/api/v1/me/2fa/verify", staff(VerifyTwoFactor))
+	r.POST("/api/v1/me/2fa/disable", staff(DisableTwoFactor))
+}
+
+// EnrollTwoFactor generates a fresh TOTP secret for the staff account and
+// returns it with an otpauth:// URL for authenticator apps. The secret stays
+// disabled until VerifyTwoFactor proves the app produces matching codes.
+func EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	claims := currentClaims(r)
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	_, err = db.Exec(
+		`INSERT INTO two_factor (account_id, role, secret, enabled, created_at)
+		 VALUES (?, ?, ?, 0, ?)
+		 ON DUPLICATE KEY UPDATE secret = VALUES(secret), enabled = 0`,
+		claims.ID, claims.Role, secret, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"secret": secret,
+		"otpauth_url": fmt.Sprintf("otpauth://totp/UTS-PPT:%s-%d?secret=%s&issuer=%s",
+			claims.Role, claims.ID, secret, url.QueryEscape("UTS-PPT")),
+	})
+}
+
+// VerifyTwoFactor enables 2FA after the first valid code and hands out the
+// recovery codes exactly once; only their hashes are kept.
+func VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Code == "" {
+		respondError(w, http.StatusBadRequest, "code wajib diisi")
+		return
+	}
+	claims := currentClaims(r)
+	var (
+		secret  string
+		enabled bool
+	)
+	err := db.QueryRow("SELECT secret, enabled FROM two_factor WHERE account_id = ? AND role = ?",
+		claims.ID, claims.Role).Scan(&secret, &enabled)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "belum ada enrollment 2FA")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if enabled {
+		respondError(w, http.StatusConflict, "2FA sudah aktif")
+		return
+	}
+	if !validateTOTP(secret, req.Code) {
+		respondError(w, http.StatusUnauthorized, "kode TOTP salah")
+		return
+	}
+	codes := make([]string, 8)
+	err = WithTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("UPDATE two_factor SET enabled = 1 WHERE account_id = ? AND role = ?",
+			claims.ID, claims.Role); err != nil {
+			return err
+		}
+		if _, err := tx.Exec("DELETE FROM two_factor_recovery_codes WHERE account_id = ? AND role = ?",
+			claims.ID, claims.Role); err != nil {
+			return err
+		}
+		for i := range codes {
+			raw := make([]byte, 5)
+			if _, err := rand.Read(raw); err != nil {
+				return err
+			}
+			codes[i] = hex.EncodeToString(raw)
+			if _, err := tx.Exec(
+				"INSERT INTO two_factor_recovery_codes (account_id, role, code_hash, created_at) VALUES (?, ?, ?, ?)",
+				claims.ID, claims.Role, hashRefreshToken(codes[i]), nowUTC()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"message":        "2FA aktif, simpan recovery codes ini baik-baik",
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTwoFactor turns 2FA off again; it demands a current code so a
+// hijacked session can't silently weaken the account.
+func DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Code == "" {
+		respondError(w, http.StatusBadRequest, "code wajib diisi")
+		return
+	}
+	claims := currentClaims(r)
+	ok, err := checkTwoFactor(claims.ID, claims.Role, req.Code)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "kode TOTP salah")
+		return
+	}
+	err = WithTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec("DELETE FROM two_factor WHERE account_id = ? AND role = ?",
+			claims.ID, claims.Role); err != nil {
+			return err
+		}
+		_, err := tx.Exec("DELETE FROM two_factor_recovery_codes WHERE account_id = ? AND role = ?",
+			claims.ID, claims.Role)
+		return err
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondMessage(w, http.StatusOK, "2FA dimatikan")
+}
+
+// twoFactorEnabled reports whether the account finished 2FA enrollment.
+func twoFactorEnabled(accountID int64, role string) (bool, error) {
+	var enabled bool
+	err := db.QueryRow("SELECT enabled FROM two_factor WHERE account_id = ? AND role = ?",
+		accountID, role).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return enabled, err
+}
+
+// checkTwoFactor accepts either a live TOTP code or an unused recovery code;
+// a matched recovery code is burned on the spot.
+func checkTwoFactor(accountID int64, role, code string) (bool, error) {
+	var secret string
+	err := db.QueryRow("SELECT secret FROM two_factor WHERE account_id = ? AND role = ? AND enabled = 1",
+		accountID, role).Scan(&secret)
+	if err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if validateTOTP(secret, code) {
+		return true, nil
+	}
+	res, err := db.Exec(
+		"DELETE FROM two_factor_recovery_codes WHERE account_id = ? AND role = ? AND code_hash = ?",
+		accountID, role, hashRefreshToken(code))
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// dbtx is the subset of database/sql shared by *sql.DB and *sql.Tx, so
+// helpers can run both inside and outside a transaction.
+type dbtx interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// txMaxAttempts is how many times WithTx retries a retryable failure.
+const txMaxAttempts = 3
+
+// WithTx runs fn inside a transaction, committing on success and rolling back
+// on error. Deadlocks and lock wait timeouts are retried with backoff because
+// MySQL asks the loser of a deadlock to simply try again.
+func WithTx(fn func(tx *sql.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= txMaxAttempts; attempt++ {
+		err = runTx(fn)
+		if err == nil || !isRetryableTxError(err) || attempt == txMaxAttempts {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * 50 * time.Millisecond)
+	}
+	return err
+}
+
+func runTx(fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// isRetryableTxError reports whether the error is a MySQL deadlock (1213) or
+// lock wait timeout (1205).
+func isRetryableTxError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "Error 1213") ||
+		strings.Contains(msg, "Error 1205") ||
+		strings.Contains(msg, "Deadlock found") ||
+		strings.Contains(msg, "Lock wait timeout")
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"database/sql"
+	"net/http"
+)
+
+func registerWarehouseRoutes(r *Router) {
+	r.GET("/api/v1/warehouses", RoleMiddleware("admin", "stocker")(GetWarehouses))
+	r.POST("/api/v1/warehouses", RoleMiddleware("admin")(CreateWarehouse))
+	r.POST("/api/v1/warehouses/:id/stock", PermissionMiddleware("stock:write")(AdjustWarehouseStock))
+	r.GET("/api/v1/products/:id/warehouse-stock", RoleMiddleware("admin", "stocker")(GetProductWarehouseStock))
+}
+
+// GetWarehouses lists every warehouse.
+func GetWarehouses(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query("SELECT id, name, location FROM warehouses")
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	warehouses := []Warehouse{}
+	for rows.Next() {
+		var wh Warehouse
+		if err := rows.Scan(&wh.ID, &wh.Name, &wh.Location); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		warehouses = append(warehouses, wh)
+	}
+	respondJSON(w, http.StatusOK, warehouses)
+}
+
+// CreateWarehouse inserts a warehouse.
+func CreateWarehouse(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name     string `json:"name"`
+		Location string `json:"location"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Name == "" {
+		respondError(w, http.StatusBadRequest, "nama warehouse wajib diisi")
+		return
+	}
+	res, err := db.Exec("INSERT INTO warehouses (name, location) VALUES (?, ?)", req.Name, req.Location)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "warehouse berhasil dibuat"})
+}
+
+// AdjustWarehouseStock shifts per-warehouse stock for a product or variant
+// and keeps the aggregate stock column in sync through the stock service.
+func AdjustWarehouseStock(w http.ResponseWriter, r *http.Request) {
+	warehouseID, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	var req struct {
+		ProductID int64  `json:"product_id"`
+		VariantID *int64 `json:"variant_id"`
+		Delta     int    `json:"delta"`
+	}
+	if err := decodeBody(r, &req); err != nil || req.Delta == 0 {
+		respondError(w, http.StatusBadRequest, "delta tidak boleh nol")
+		return
+	}
+	err = applyWarehouseStockDelta(warehouseID, req.ProductID, req.VariantID, req.Delta, currentUserID(r))
+	if err == errWarehouseStockMissing {
+		respondError(w, http.StatusNotFound, "stock warehouse tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if req.Delta > 0 {
+		var isPreorder bool
+		if err := db.QueryRow("SELECT is_preorder FROM products WHERE id = ?",
+			req.ProductID).Scan(&isPreorder); err == nil && isPreorder {
+			notifyPreorderArrival(req.ProductID)
+		}
+	}
+	respondMessage(w, http.StatusOK, "stock warehouse berhasil diupdate")
+}
+
+// GetProductWarehouseStock shows where a product's stock physically sits: one
+// row per (warehouse, variant) pair plus the aggregate total.
+func GetProductWarehouseStock(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	rows, err := db.Query(
+		`SELECT id, warehouse_id, product_id, variant_id, stock FROM warehouse_stock
+		 WHERE product_id = ? ORDER BY warehouse_id, variant_id`, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	entries := []WarehouseStock{}
+	total := 0
+	for rows.Next() {
+		var (
+			ws        WarehouseStock
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&ws.ID, &ws.WarehouseID, &ws.ProductID, &variantID, &ws.Stock); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if variantID.Valid {
+			ws.VariantID = &variantID.Int64
+		}
+		total += ws.Stock
+		entries = append(entries, ws)
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"entries": entries,
+		"total":   total,
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// WhatsAppSender delivers one WhatsApp message. Most Indonesian customers
+// read WhatsApp long before email, so notifications fan out here too.
+type WhatsAppSender interface {
+	SendWhatsApp(to, message string) error
+}
+
+func newWhatsAppSender() WhatsAppSender {
+	switch config.WAProvider {
+	case "meta":
+		return metaWhatsAppSender{}
+	default:
+		return logWhatsAppSender{}
+	}
+}
+
+// logWhatsAppSender is the dev implementation.
+type logWhatsAppSender struct{}
+
+func (logWhatsAppSender) SendWhatsApp(to, message string) error {
+	log.Printf("whatsapp (log-only) ke %s: %s", to, message)
+	return nil
+}
+
+// metaWhatsAppSender sends through the WhatsApp Business Cloud API.
+type metaWhatsAppSender struct{}
+
+func (metaWhatsAppSender) SendWhatsApp(to, message string) error {
+	payload, err := json.Marshal(map[string]any{
+		"messaging_product": "whatsapp",
+		"to":                to,
+		"type":              "text",
+		"text":              map[string]string{"body": message},
+	})
+	if err != nil {
+		return err
+	}
+	endpoint := "https://graph.facebook.com/v19.0/" + config.WAPhoneID + "/messages"
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+config.WAToken)
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("whatsapp api menjawab status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+var waSender WhatsAppSender = logWhatsAppSender{}
+
+// initWhatsAppSender picks the configured implementation; called from main
+// after LoadConfig.
+func initWhatsAppSender() {
+	waSender = newWhatsAppSender()
+}
+
+// sendWhatsAppAsync fires a message in the background, logging failures.
+func sendWhatsAppAsync(to, message string) {
+	go func() {
+		if err := waSender.SendWhatsApp(to, message); err != nil {
+			log.Printf("gagal kirim whatsapp ke %s: %v", to, err)
+		}
+	}()
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+)
+
+func registerWishlistRoutes(r *Router) {
+	r.GET("/api/v1/wishlist", AuthMiddleware(MyWishlist))
+	r.POST("/api/v1/wishlist", AuthMiddleware(CreateWishlistItem))
+	r.DELETE("/api/v1/wishlist/:id", AuthMiddleware(DeleteWishlistItem))
+	r.POST("/api/v1/wishlist/:id/move-to-cart", AuthMiddleware(MoveWishlistItemToCart))
+}
+
+// WishlistItem is a product a user saved for later.
+type WishlistItem struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	ProductID int64     `json:"product_id"`
+	VariantID *int64    `json:"variant_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MyWishlist lists the authenticated user's wishlist.
+func MyWishlist(w http.ResponseWriter, r *http.Request) {
+	rows, err := db.Query(
+		"SELECT id, user_id, product_id, variant_id, created_at FROM wishlists WHERE user_id = ? ORDER BY created_at DESC",
+		currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer rows.Close()
+	items := []WishlistItem{}
+	for rows.Next() {
+		var (
+			it        WishlistItem
+			variantID sql.NullInt64
+		)
+		if err := rows.Scan(&it.ID, &it.UserID, &it.ProductID, &variantID, &it.CreatedAt); err != nil {
+			respondError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if variantID.Valid {
+			it.VariantID = &variantID.Int64
+		}
+		items = append(items, it)
+	}
+	respondJSON(w, http.StatusOK, items)
+}
+
+// CreateWishlistItem adds a product (or variant) to the user's wishlist.
+func CreateWishlistItem(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ProductID int64  `json:"product_id"`
+		VariantID *int64 `json:"variant_id"`
+	}
+	if err := decodeBody(r, &req); err != nil {
+		respondError(w, http.StatusBadRequest, "body tidak valid")
+		return
+	}
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM products WHERE id = ?", req.ProductID).Scan(&exists); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if exists == 0 {
+		respondError(w, http.StatusNotFound, "produk tidak ditemukan")
+		return
+	}
+	userID := currentUserID(r)
+	var duplicate int
+	err := db.QueryRow(
+		"SELECT COUNT(*) FROM wishlists WHERE user_id = ? AND product_id = ? AND variant_id <=> ?",
+		userID, req.ProductID, req.VariantID).Scan(&duplicate)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if duplicate > 0 {
+		respondError(w, http.StatusConflict, "produk sudah ada di wishlist")
+		return
+	}
+	res, err := db.Exec(
+		"INSERT INTO wishlists (user_id, product_id, variant_id, created_at) VALUES (?, ?, ?, ?)",
+		userID, req.ProductID, req.VariantID, nowUTC())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	id, _ := res.LastInsertId()
+	respondJSON(w, http.StatusCreated, map[string]any{"id": id, "message": "produk ditambahkan ke wishlist"})
+}
+
+// DeleteWishlistItem removes an entry from the user's wishlist.
+func DeleteWishlistItem(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	res, err := db.Exec("DELETE FROM wishlists WHERE id = ? AND user_id = ?", id, currentUserID(r))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		respondError(w, http.StatusNotFound, "item wishlist tidak ditemukan")
+		return
+	}
+	respondMessage(w, http.StatusOK, "item wishlist berhasil dihapus")
+}
+
+// MoveWishlistItemToCart moves a wishlist entry into the user's cart with quantity 1.
+func MoveWishlistItemToCart(w http.ResponseWriter, r *http.Request) {
+	id, err := paramInt(r, "id")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "id tidak valid")
+		return
+	}
+	userID := currentUserID(r)
+	var (
+		productID int64
+		variantID sql.NullInt64
+	)
+	err = db.QueryRow("SELECT product_id, variant_id FROM wishlists WHERE id = ? AND user_id = ?",
+		id, userID).Scan(&productID, &variantID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "item wishlist tidak ditemukan")
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var vID *int64
+	if variantID.Valid {
+		vID = &variantID.Int64
+	}
+	price, stock, isPreorder, err := resolveItemPrice(productID, vID)
+	if err == sql.ErrNoRows {
+		respondError(w, http.StatusNotFound, "produk atau varian tidak ditemukan")
+		return
+	} else if err == errVariantRequired {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if stock < 1 && !isPreorder {
+		respondError(w, http.StatusBadRequest, "stock tidak mencukupi")
+		return
+	}
+	cartID, err := getOrCreateCart(userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var itemID int64
+	err = WithTx(func(tx *sql.Tx) error {
+		res, err := tx.Exec(
+			"INSERT INTO cart_items (cart_id, product_id, variant_id, quantity, price) VALUES (?, ?, ?, 1, ?)",
+			cartID, productID, vID, price)
+		if err != nil {
+			return err
+		}
+		itemID, _ = res.LastInsertId()
+		if err := AddToCartTotalPrice(tx, cartID, price); err != nil {
+			return err
+		}
+		_, err = tx.Exec("DELETE FROM wishlists WHERE id = ?", id)
+		return err
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	respondJSON(w, http.StatusCreated, map[string]any{"cart_item_id": itemID, "message": "item dipindahkan ke cart"})
+}
+
+// notifyWishlistDiscount tells every user who wishlisted the product that it is
+// now discounted.
+func notifyWishlistDiscount(productID int64) {
+	var name string
+	if err := db.QueryRow("SELECT name FROM products WHERE id = ?", productID).Scan(&name); err != nil {
+		log.Printf("gagal ambil nama produk %d: %v", productID, err)
+		return
+	}
+	rows, err := db.Query("SELECT DISTINCT user_id FROM wishlists WHERE product_id = ?", productID)
+	if err != nil {
+		log.Printf("gagal ambil wishlist produk %d: %v", productID, err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			log.Printf("gagal scan wishlist produk %d: %v", productID, err)
+			return
+		}
+		createNotification(userID, "Produk wishlist diskon",
+			"Produk \""+name+"\" di wishlist kamu sedang diskon. Cek sekarang!")
+	}
+}